@@ -0,0 +1,141 @@
+// Package cache stores rank results keyed by the hash of the ranked graph
+// so ranking can be skipped when a graph didn't change, see rep.Graph.RankCached and detrep.Graph.RankCached
+package cache
+
+import (
+	"container/list"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache maps graph hashes to encoded results
+// implementations must be safe for concurrent use
+type Cache interface {
+	// Get returns the value stored under key, false if there is none
+	Get(key []byte) ([]byte, bool, error)
+	// Set stores value under key
+	Set(key []byte, value []byte) error
+}
+
+// Memory is an in-memory Cache that evicts the least recently used entries
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+type entry struct {
+	key   string
+	value []byte
+}
+
+// NewMemory returns an in-memory cache holding at most size entries, size <= 0 means no limit
+func NewMemory(size int) *Memory {
+	return &Memory{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Get implements Cache
+func (m *Memory) Get(key []byte) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[string(key)]
+	if !ok {
+		return nil, false, nil
+	}
+	m.order.MoveToFront(element)
+	// callers may modify the value
+	return append([]byte{}, element.Value.(*entry).value...), true, nil
+}
+
+// Set implements Cache
+func (m *Memory) Set(key []byte, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value = append([]byte{}, value...)
+
+	if element, ok := m.entries[string(key)]; ok {
+		element.Value.(*entry).value = value
+		m.order.MoveToFront(element)
+		return nil
+	}
+	m.entries[string(key)] = m.order.PushFront(&entry{key: string(key), value: value})
+
+	if m.size > 0 && m.order.Len() > m.size {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*entry).key)
+	}
+	return nil
+}
+
+// Len returns the number of entries
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// File is a Cache that stores every entry in its own file, named after the hex encoded key
+// entries are never evicted
+type File struct {
+	Dir string
+}
+
+// NewFile returns a file cache in dir, the directory is created if it doesn't exist
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &File{Dir: dir}, nil
+}
+
+func (f *File) path(key []byte) string {
+	return filepath.Join(f.Dir, hex.EncodeToString(key))
+}
+
+// Get implements Cache
+func (f *File) Get(key []byte) ([]byte, bool, error) {
+	value, err := ioutil.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements Cache
+// the value is written to a temporary file first so readers never see a partial entry
+func (f *File) Set(key []byte, value []byte) error {
+	tmp, err := ioutil.TempFile(f.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), f.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cache: %w", err)
+	}
+	return nil
+}
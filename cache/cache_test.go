@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func testCache(t *testing.T, c Cache) {
+	if _, ok, err := c.Get([]byte("missing")); ok || err != nil {
+		t.Errorf("expected a miss, got %v %v", ok, err)
+	}
+	if err := c.Set([]byte{1, 2}, []byte("results")); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok, err := c.Get([]byte{1, 2}); !ok || err != nil || !bytes.Equal(value, []byte("results")) {
+		t.Errorf("expected a hit, got %q %v %v", value, ok, err)
+	}
+	if err := c.Set([]byte{1, 2}, []byte("updated")); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := c.Get([]byte{1, 2}); !bytes.Equal(value, []byte("updated")) {
+		t.Errorf("expected the updated value, got %q", value)
+	}
+
+	// changing a value that was set or returned doesn't change the cached one
+	value := []byte("stored")
+	c.Set([]byte{3}, value)
+	value[0] = 'x'
+	got, _, _ := c.Get([]byte{3})
+	got[1] = 'x'
+	if again, _, _ := c.Get([]byte{3}); !bytes.Equal(again, []byte("stored")) {
+		t.Errorf("the cached value was modified: %q", again)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testCache(t, NewMemory(0))
+
+	m := NewMemory(2)
+	m.Set([]byte("a"), []byte("1"))
+	m.Set([]byte("b"), []byte("2"))
+	m.Get([]byte("a"))
+	m.Set([]byte("c"), []byte("3"))
+
+	if m.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", m.Len())
+	}
+	if _, ok, _ := m.Get([]byte("b")); ok {
+		t.Error("the least recently used entry should be evicted")
+	}
+	if _, ok, _ := m.Get([]byte("a")); !ok {
+		t.Error("a was used recently and should be kept")
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f, err := NewFile(dir + "/results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCache(t, f)
+
+	// entries survive a new cache on the same directory
+	again, err := NewFile(dir + "/results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, ok, _ := again.Get([]byte{1, 2}); !ok || !bytes.Equal(value, []byte("updated")) {
+		t.Errorf("expected the stored value, got %q", value)
+	}
+}
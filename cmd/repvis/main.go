@@ -0,0 +1,136 @@
+// Command repvis renders a reputation graph snapshot as an html page
+//
+// usage:
+//
+//	repvis [-graph graph.json] [-results results.json] [-out index.html] [-passes 2] [-evolution] [-trace id] [-serve 7000]
+//
+// without a graph file the built-in example graph is rendered
+// -evolution adds a chart of the ranks over all iterations and passes
+// -trace id adds a chart of the paths that carry the most rank from the personalization nodes to id
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/components"
+	rep "github.com/relevant-community/reputation/rep"
+	"github.com/relevant-community/reputation/vis"
+)
+
+func main() {
+	graphPath := flag.String("graph", "", "path to a json graph snapshot (defaults to the built-in example)")
+	resultsPath := flag.String("results", "", "path to a json results file, the graph is ranked when omitted")
+	out := flag.String("out", "index.html", "output html file")
+	passes := flag.Int("passes", 2, "number of rank passes, each pass uses the previous results as cached ranks")
+	title := flag.String("title", "", "chart title")
+	evolution := flag.Bool("evolution", false, "also chart how ranks change over the iterations of each pass")
+	trace := flag.String("trace", "", "also chart the top trust paths from the personalization nodes to this node")
+	paths := flag.Int("paths", 5, "number of trust paths to chart with -trace")
+	depth := flag.Int("depth", 6, "max number of hops of the trust paths charted with -trace, 0 means no limit")
+	serve := flag.String("serve", "", "serve the output directory on this port after rendering")
+	solver := flag.String("solver", "", "rank with this solver: power, jacobi, gauss-seidel or extrapolation (defaults to the snapshot solver)")
+	flag.Parse()
+
+	snapshot := vis.Graph1
+	chartTitle := vis.Graph1Title
+	if *graphPath != "" {
+		s, err := readSnapshot(*graphPath)
+		if err != nil {
+			log.Fatalf("reading graph: %v", err)
+		}
+		snapshot = *s
+		chartTitle = filepath.Base(*graphPath)
+	}
+	if *title != "" {
+		chartTitle = *title
+	}
+	if *solver != "" {
+		s, err := rep.ParseSolver(*solver)
+		if err != nil {
+			log.Fatal(err)
+		}
+		snapshot.Solver = s
+	}
+
+	var graph *rep.Graph
+	var results map[string]rep.Ranks
+	recorder := &vis.Recorder{}
+	switch {
+	case *resultsPath != "":
+		r, err := readResults(*resultsPath)
+		if err != nil {
+			log.Fatalf("reading results: %v", err)
+		}
+		graph, results = snapshot.Graph(), r
+	case *evolution:
+		graph, results = snapshot.RankObserved(*passes, recorder)
+	default:
+		graph, results = snapshot.Rank(*passes)
+	}
+	if iterations, err := graph.Iterations(); err == nil {
+		log.Printf("ranked with %s in %d iterations", snapshot.Solver, iterations)
+	}
+
+	charts := []components.Charter{vis.NewGraphChart(graph, results, chartTitle)}
+	if len(recorder.Steps) > 0 {
+		charts = append(charts, vis.NewEvolutionChart(recorder.Steps, "rank evolution"))
+	}
+	if *trace != "" {
+		tracePaths, err := graph.TracePaths(*trace, *paths, *depth)
+		if err != nil {
+			log.Fatalf("tracing paths: %v", err)
+		}
+		charts = append(charts, vis.NewPathsChart(graph, results, tracePaths, "trust paths to "+*trace))
+	}
+
+	if err := render(*out, charts...); err != nil {
+		log.Fatalf("rendering: %v", err)
+	}
+	log.Printf("wrote %s", *out)
+
+	if *serve == "" {
+		return
+	}
+	addr := *serve
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	dir := filepath.Dir(*out)
+	log.Printf("serving %s on %s", dir, addr)
+	log.Fatal(http.ListenAndServe(addr, http.FileServer(http.Dir(dir))))
+}
+
+func render(path string, charts ...components.Charter) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := vis.Render(f, charts...); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readSnapshot(path string) (*rep.Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return rep.ReadSnapshot(f)
+}
+
+func readResults(path string) (map[string]rep.Ranks, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return rep.ReadResults(f)
+}
@@ -0,0 +1,168 @@
+// Package attestation lets off-chain workers sign the results of a detrep computation
+// an attestation binds the results commitment to the graph it was computed from,
+// the rank parameters and an epoch, and is signed with ed25519
+// attestations of several workers can be aggregated to reach a quorum on identical results
+package attestation
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/relevant-community/reputation/detrep"
+)
+
+// signPrefix separates attestation sign bytes from anything else signed with the same key
+const signPrefix = "detrep/attestation/v1"
+
+var (
+	// ErrInvalidSignature is returned when a signature doesn't match the attestation and signer
+	ErrInvalidSignature = errors.New("invalid attestation signature")
+	// ErrNoQuorum is returned when no attestation is signed by enough trusted workers
+	ErrNoQuorum = errors.New("no quorum")
+)
+
+// Params are the rank parameters the results were computed with
+type Params struct {
+	Alpha   detrep.Uint `json:"alpha"`   // α, with detrep precision
+	Epsilon detrep.Uint `json:"epsilon"` // ε, with detrep precision
+}
+
+// Attestation is the claim that ranking a graph with the given params produced the committed results
+type Attestation struct {
+	Epoch      uint64 `json:"epoch"`
+	GraphHash  []byte `json:"graphHash"`  // hash of the ranked graph, see detrep.Graph.Hash
+	Params     Params `json:"params"`     // rank parameters
+	Commitment []byte `json:"commitment"` // detrep.Commitment of the results
+}
+
+// New returns the attestation of results
+func New(epoch uint64, graphHash []byte, params Params, results map[string]detrep.Ranks) Attestation {
+	return Attestation{
+		Epoch:      epoch,
+		GraphHash:  graphHash,
+		Params:     params,
+		Commitment: detrep.Commitment(results),
+	}
+}
+
+// SignBytes returns the canonical encoding of the attestation that is signed
+func (a Attestation) SignBytes() []byte {
+	bz := []byte(signPrefix)
+	var epoch [8]byte
+	binary.BigEndian.PutUint64(epoch[:], a.Epoch)
+	bz = append(bz, epoch[:]...)
+	bz = appendLengthPrefixed(bz, a.GraphHash)
+	bz = appendLengthPrefixed(bz, uintBytes(a.Params.Alpha))
+	bz = appendLengthPrefixed(bz, uintBytes(a.Params.Epsilon))
+	return appendLengthPrefixed(bz, a.Commitment)
+}
+
+// Equal returns true if both attestations make the same claim
+func (a Attestation) Equal(other Attestation) bool {
+	return bytes.Equal(a.SignBytes(), other.SignBytes())
+}
+
+// SignedAttestation is an attestation signed by a worker
+type SignedAttestation struct {
+	Attestation Attestation       `json:"attestation"`
+	Signer      ed25519.PublicKey `json:"signer"`
+	Signature   []byte            `json:"signature"`
+}
+
+// Sign signs an attestation
+func Sign(a Attestation, key ed25519.PrivateKey) SignedAttestation {
+	return SignedAttestation{
+		Attestation: a,
+		Signer:      key.Public().(ed25519.PublicKey),
+		Signature:   ed25519.Sign(key, a.SignBytes()),
+	}
+}
+
+// Verify checks the signature of the attestation
+func (s SignedAttestation) Verify() error {
+	if len(s.Signer) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key length %d", ErrInvalidSignature, len(s.Signer))
+	}
+	if !ed25519.Verify(s.Signer, s.Attestation.SignBytes(), s.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Quorum is an attestation with the trusted workers that signed it
+type Quorum struct {
+	Attestation Attestation
+	Signers     []ed25519.PublicKey // sorted
+}
+
+// Aggregate returns the attestation signed by at least threshold of the trusted workers
+// attestations with an invalid signature or from untrusted workers are ignored
+// and every worker is counted once per attestation
+// if more than one attestation reaches the threshold the workers disagree and an error is returned,
+// a threshold of more than half of the trusted workers guarantees this can't happen
+func Aggregate(attestations []SignedAttestation, trusted []ed25519.PublicKey, threshold int) (Quorum, error) {
+	if threshold <= 0 {
+		return Quorum{}, fmt.Errorf("threshold must be positive: %d", threshold)
+	}
+	isTrusted := map[string]bool{}
+	for _, key := range trusted {
+		isTrusted[string(key)] = true
+	}
+
+	type group struct {
+		attestation Attestation
+		signers     map[string]ed25519.PublicKey
+	}
+	groups := map[string]*group{}
+	for _, signed := range attestations {
+		if !isTrusted[string(signed.Signer)] || signed.Verify() != nil {
+			continue
+		}
+		claim := string(signed.Attestation.SignBytes())
+		if _, ok := groups[claim]; !ok {
+			groups[claim] = &group{attestation: signed.Attestation, signers: map[string]ed25519.PublicKey{}}
+		}
+		groups[claim].signers[string(signed.Signer)] = signed.Signer
+	}
+
+	var quorums []Quorum
+	for _, g := range groups {
+		if len(g.signers) < threshold {
+			continue
+		}
+		signers := make([]ed25519.PublicKey, 0, len(g.signers))
+		for _, signer := range g.signers {
+			signers = append(signers, signer)
+		}
+		sort.Slice(signers, func(i, j int) bool { return bytes.Compare(signers[i], signers[j]) < 0 })
+		quorums = append(quorums, Quorum{Attestation: g.attestation, Signers: signers})
+	}
+
+	switch len(quorums) {
+	case 0:
+		return Quorum{}, fmt.Errorf("%w: no attestation is signed by %d trusted workers", ErrNoQuorum, threshold)
+	case 1:
+		return quorums[0], nil
+	default:
+		return Quorum{}, fmt.Errorf("%w: %d conflicting attestations reached the threshold", ErrNoQuorum, len(quorums))
+	}
+}
+
+func appendLengthPrefixed(bz []byte, value []byte) []byte {
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(value)))
+	bz = append(bz, length[:n]...)
+	return append(bz, value...)
+}
+
+// uintBytes returns the big-endian bytes of u, a nil Uint is encoded like 0
+func uintBytes(u detrep.Uint) []byte {
+	if u == (detrep.Uint{}) {
+		return nil
+	}
+	return u.BigInt().Bytes()
+}
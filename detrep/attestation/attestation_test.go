@@ -0,0 +1,126 @@
+package attestation
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/relevant-community/reputation/detrep"
+)
+
+func testKeys(n int) []ed25519.PrivateKey {
+	keys := make([]ed25519.PrivateKey, n)
+	for i := range keys {
+		seed := bytes.Repeat([]byte{byte(i + 1)}, ed25519.SeedSize)
+		keys[i] = ed25519.NewKeyFromSeed(seed)
+	}
+	return keys
+}
+
+func publicKeys(keys []ed25519.PrivateKey) []ed25519.PublicKey {
+	public := make([]ed25519.PublicKey, len(keys))
+	for i, key := range keys {
+		public[i] = key.Public().(ed25519.PublicKey)
+	}
+	return public
+}
+
+func testAttestation(pRank uint64) Attestation {
+	results := map[string]detrep.Ranks{
+		"a": {ID: "a", PRank: detrep.NewUint(pRank), NRank: detrep.ZeroUint()},
+		"b": {ID: "b", PRank: detrep.NewUint(1000 - pRank), NRank: detrep.NewUint(5)},
+	}
+	params := Params{Alpha: detrep.FtoBD(0.85), Epsilon: detrep.FtoBD(0.000001)}
+	return New(7, []byte("graph hash"), params, results)
+}
+
+func TestSignVerify(t *testing.T) {
+	key := testKeys(1)[0]
+	signed := Sign(testAttestation(600), key)
+	if err := signed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// signed attestations can be sent as json
+	bz, err := json.Marshal(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded SignedAttestation
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Verify(); err != nil {
+		t.Errorf("decoded attestation should verify: %v", err)
+	}
+
+	for name, change := range map[string]func(a *SignedAttestation){
+		"epoch":      func(a *SignedAttestation) { a.Attestation.Epoch++ },
+		"graph hash": func(a *SignedAttestation) { a.Attestation.GraphHash = []byte("other graph") },
+		"alpha":      func(a *SignedAttestation) { a.Attestation.Params.Alpha = detrep.FtoBD(0.8) },
+		"epsilon":    func(a *SignedAttestation) { a.Attestation.Params.Epsilon = detrep.FtoBD(0.0001) },
+		"commitment": func(a *SignedAttestation) { a.Attestation.Commitment = testAttestation(601).Commitment },
+		"signer":     func(a *SignedAttestation) { a.Signer = publicKeys(testKeys(2))[1] },
+		"public key": func(a *SignedAttestation) { a.Signer = a.Signer[1:] },
+	} {
+		tampered := Sign(testAttestation(600), key)
+		change(&tampered)
+		if err := tampered.Verify(); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: tampered attestation should not verify", name)
+		}
+	}
+}
+
+func TestSignBytesUnambiguous(t *testing.T) {
+	a := testAttestation(600)
+	b := a
+	// move a byte from the graph hash to the commitment
+	b.GraphHash = a.GraphHash[:len(a.GraphHash)-1]
+	b.Commitment = append([]byte{a.GraphHash[len(a.GraphHash)-1]}, a.Commitment...)
+	if a.Equal(b) {
+		t.Error("different attestations should have different sign bytes")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	keys := testKeys(5)
+	trusted := publicKeys(keys[:4])
+	honest, dishonest := testAttestation(600), testAttestation(900)
+
+	attestations := []SignedAttestation{
+		Sign(honest, keys[0]),
+		Sign(honest, keys[0]), // counted once
+		Sign(honest, keys[1]),
+		Sign(dishonest, keys[2]),
+		Sign(honest, keys[4]), // not trusted
+	}
+	forged := Sign(honest, keys[3])
+	forged.Signature[0] ^= 1
+	attestations = append(attestations, forged)
+
+	if _, err := Aggregate(attestations, trusted, 3); !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("duplicate, untrusted and forged attestations should not count, got %v", err)
+	}
+
+	attestations = append(attestations, Sign(honest, keys[3]))
+	quorum, err := Aggregate(attestations, trusted, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !quorum.Attestation.Equal(honest) {
+		t.Error("expected the honest attestation")
+	}
+	if len(quorum.Signers) != 3 {
+		t.Errorf("expected 3 signers, got %d", len(quorum.Signers))
+	}
+
+	// with a low threshold both attestations reach it
+	if _, err := Aggregate(attestations, trusted, 1); !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("conflicting attestations should not reach a quorum, got %v", err)
+	}
+	if _, err := Aggregate(attestations, trusted, 0); err == nil {
+		t.Error("expected an error for a threshold of 0")
+	}
+}
@@ -0,0 +1,45 @@
+package detrep
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// SortedResults returns the results in id order, the order Rank emits them in
+// the ID of every result is set to its key
+func SortedResults(results map[string]Ranks) []Ranks {
+	sorted := make([]Ranks, 0, len(results))
+	for id, result := range results {
+		result.ID = id
+		sorted = append(sorted, result)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// EncodeResult returns the canonical encoding of a result:
+// the id, pRank and nRank, each prefixed with its uvarint length
+// ranks are big-endian and without leading zeros, so 0 is encoded as an empty value
+func EncodeResult(result Ranks) []byte {
+	bz := appendLengthPrefixed(nil, []byte(result.ID))
+	bz = appendLengthPrefixed(bz, uintBytes(result.PRank))
+	return appendLengthPrefixed(bz, uintBytes(result.NRank))
+}
+
+// Commitment returns the merkle root of the results, see MerkleTree
+// nodes that rank the same graph get the same commitment, so it can be stored in app state
+// and compared across nodes, and the ranks of a single node can be proven against it
+func Commitment(results map[string]Ranks) []byte {
+	return NewMerkleTree(results).Root()
+}
+
+func appendLengthPrefixed(bz []byte, value []byte) []byte {
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(value)))
+	bz = append(bz, length[:n]...)
+	return append(bz, value...)
+}
+
+func uintBytes(u Uint) []byte {
+	return u.BigInt().Bytes()
+}
@@ -0,0 +1,61 @@
+package detrep
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+)
+
+func TestResultOrder(t *testing.T) {
+	for run := 0; run < 5; run++ {
+		graph := NewGraphHelper(0.85, 0.000001, zero)
+		a := NewNodeInputHelper("a", 0, 0)
+		graph.AddPersonalizationNode(a)
+		graph.LinkHelper(a, NewNodeInputHelper("e", 0, 0), 1.0)
+		graph.LinkHelper(a, NewNodeInputHelper("d", 0, 0), -1.0)
+		graph.LinkHelper(a, NewNodeInputHelper("c", 0, 0), 1.0)
+		graph.LinkHelper(a, NewNodeInputHelper("b", 0, 0), 1.0)
+
+		ids := []string{}
+		graph.Rank(func(id string, pRank Uint, nRank Uint) {
+			ids = append(ids, id)
+		})
+		if !sort.StringsAreSorted(ids) || len(ids) != 5 {
+			t.Fatalf("results should be emitted in id order, got %v", ids)
+		}
+	}
+}
+
+func TestCommitment(t *testing.T) {
+	_, results := rankTwice(func(graph *Graph, n map[string]Node) {
+		graph.LinkHelper(n["a"], n["b"], 2.0)
+		graph.LinkHelper(n["a"], n["c"], 1.0)
+		graph.LinkHelper(n["b"], n["c"], -1.0)
+	})
+	commitment := Commitment(results)
+
+	// same results, built in a different order and without ids
+	copied := map[string]Ranks{}
+	for id, result := range results {
+		copied[id] = Ranks{PRank: result.PRank, NRank: result.NRank}
+	}
+	if !bytes.Equal(commitment, Commitment(copied)) {
+		t.Error("commitment should not depend on the map or the result ids")
+	}
+
+	changed := map[string]Ranks{}
+	for id, result := range results {
+		changed[id] = result
+	}
+	changed["c"] = Ranks{ID: "c", PRank: results["c"].PRank.Add(OneUint()), NRank: results["c"].NRank}
+	if bytes.Equal(commitment, Commitment(changed)) {
+		t.Error("commitment should change when a rank changes")
+	}
+
+	// ids and ranks can't run into each other
+	x := Commitment(map[string]Ranks{"a1": {PRank: NewUint(2), NRank: zero}})
+	y := Commitment(map[string]Ranks{"a": {PRank: NewUint(0x6132), NRank: zero}})
+	if bytes.Equal(x, y) {
+		t.Error("encoding should be unambiguous")
+	}
+}
@@ -0,0 +1,102 @@
+package detrep
+
+import (
+	"sort"
+)
+
+// RoundingLoss returns the rank lost to integer rounding over all the iterations of Rank
+// the lost rank (dust) is given back after every iteration so the ranks always add up to Precision:
+// personalization nodes get it in proportion to their weights, like the random jumps,
+// and all nodes get an equal share if there is no personalization vector
+func (graph *Graph) RoundingLoss() (Uint, error) {
+	if graph.rankState == nil {
+		return ZeroUint(), ErrNotRanked
+	}
+	return graph.rankState.roundingLoss, nil
+}
+
+// totalRank returns the sum of the ranks of all nodes, positive and negative
+func (graph *Graph) totalRank() Uint {
+	total := ZeroUint()
+	for _, node := range graph.Nodes {
+		total = total.Add(node.PRank)
+	}
+	return total
+}
+
+// addDust gives back rank lost to rounding
+func (graph *Graph) addDust(state *rankState, dust Uint) {
+	for key, share := range graph.dustShares(state, dust) {
+		graph.Nodes[key].PRank = graph.Nodes[key].PRank.Add(share)
+	}
+}
+
+// dustShares splits dust between the nodes that get the random jumps
+func (graph *Graph) dustShares(state *rankState, dust Uint) map[string]Uint {
+	shares := map[string]Uint{}
+	if dust.IsZero() {
+		return shares
+	}
+
+	if pVector := graph.Params.Personalization; len(pVector) > 0 {
+		for i, share := range largestRemainder(dust, state.pWeights) {
+			if existing, ok := shares[pVector[i]]; ok {
+				share = share.Add(existing)
+			}
+			shares[pVector[i]] = share
+		}
+		return shares
+	}
+
+	if len(state.keys) == 0 {
+		return shares
+	}
+	// every node has the same weight so the remainder goes to the first keys
+	n := NewUint(uint64(len(state.keys)))
+	share, remainder := dust.Quo(n), dust.Mod(n).Uint64()
+	for i, key := range state.keys {
+		if uint64(i) < remainder {
+			shares[key] = share.Add(OneUint())
+		} else {
+			shares[key] = share
+		}
+	}
+	return shares
+}
+
+// largestRemainder splits total in proportion to weights
+// the units left after the integer division go to the largest remainders, ties go to the lowest index
+func largestRemainder(total Uint, weights []Uint) []Uint {
+	sum := ZeroUint()
+	for _, weight := range weights {
+		sum = sum.Add(weight)
+	}
+
+	shares := make([]Uint, len(weights))
+	remainders := make([]Uint, len(weights))
+	left := total
+	for i, weight := range weights {
+		shares[i], remainders[i] = ZeroUint(), ZeroUint()
+		if sum.IsZero() {
+			continue
+		}
+		shares[i] = total.Mul(weight).Quo(sum)
+		remainders[i] = total.Mul(weight).Mod(sum)
+		left = left.Sub(shares[i])
+	}
+	if sum.IsZero() {
+		return shares
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]].GT(remainders[order[b]]) })
+
+	// left is smaller than the number of weights
+	for _, i := range order[:left.Uint64()] {
+		shares[i] = shares[i].Add(OneUint())
+	}
+	return shares
+}
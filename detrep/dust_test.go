@@ -0,0 +1,124 @@
+package detrep
+
+import (
+	"testing"
+)
+
+func rankResults(graph *Graph) map[string]Ranks {
+	results := map[string]Ranks{}
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
+		results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	return results
+}
+
+func total(results map[string]Ranks) Uint {
+	sum := ZeroUint()
+	for _, result := range results {
+		sum = sum.Add(result.PRank).Add(result.NRank)
+	}
+	return sum
+}
+
+func TestConservation(t *testing.T) {
+	circle := NewGraphHelper(0.85, 0.000001, zero)
+	a, b, c := NewNodeInputHelper("a", 0, 0), NewNodeInputHelper("b", 0, 0), NewNodeInputHelper("c", 0, 0)
+	circle.LinkHelper(a, b, 3.0)
+	circle.LinkHelper(b, c, 7.0)
+	circle.LinkHelper(c, a, 1.0)
+	circle.LinkHelper(a, c, -1.0)
+
+	// cached ranks that add up to more than 1
+	cached := NewGraphHelper(0.85, 0.000001, zero)
+	x, y := NewNodeInputHelper("x", 0.7, 0), NewNodeInputHelper("y", 0.6, 0.1)
+	cached.AddPersonalizationNode(x)
+	cached.LinkHelper(x, y, 1.0)
+	cached.LinkHelper(y, x, 2.0)
+
+	personalized, personalizedResults := rankTwice(verifyLinks)
+
+	for name, results := range map[string]map[string]Ranks{
+		"not personalized": rankResults(circle),
+		"cached":           rankResults(cached),
+		"personalized":     personalizedResults,
+	} {
+		if !total(results).Equal(FtoBD(1)) {
+			t.Errorf("%s: ranks should add up to exactly 1, got %s", name, total(results))
+		}
+	}
+
+	loss, err := personalized.RoundingLoss()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loss.IsZero() {
+		t.Error("expected some rank to be lost to rounding")
+	}
+	if _, err := NewGraphHelper(0.85, 0.000001, zero).RoundingLoss(); err != ErrNotRanked {
+		t.Errorf("expected ErrNotRanked, got %v", err)
+	}
+}
+
+func TestLargestRemainder(t *testing.T) {
+	cases := []struct {
+		total    uint64
+		weights  []uint64
+		expected []uint64
+	}{
+		{10, []uint64{1, 1, 1}, []uint64{4, 3, 3}},
+		{5, []uint64{3, 1}, []uint64{4, 1}},
+		{5, []uint64{1, 3}, []uint64{1, 4}},
+		{7, []uint64{2, 3, 5}, []uint64{1, 2, 4}},
+		{0, []uint64{2, 3}, []uint64{0, 0}},
+		{3, []uint64{0, 0}, []uint64{0, 0}},
+	}
+	for _, c := range cases {
+		weights := make([]Uint, len(c.weights))
+		for i, w := range c.weights {
+			weights[i] = NewUint(w)
+		}
+		shares := largestRemainder(NewUint(c.total), weights)
+		for i, share := range shares {
+			if share.Uint64() != c.expected[i] {
+				t.Errorf("%d %v: expected %v, got %v", c.total, c.weights, c.expected, shares)
+				break
+			}
+		}
+	}
+}
+
+// dustCycleGraph gives back 4 and 5 units of dust in turns, Δ used to stay at 2 units forever
+func dustCycleGraph(ε uint64) *Graph {
+	graph := NewGraphHelper(0.85, 0, zero)
+	graph.Params.ε = NewUint(ε)
+	a, b, c := NewNodeInputHelper("a", 0, 0), NewNodeInputHelper("b", 0, 0), NewNodeInputHelper("c", 0, 0)
+	d, e := NewNodeInputHelper("d", 0, 0), NewNodeInputHelper("e", 0, 0)
+	graph.AddPersonalizationNode(a)
+	graph.AddPersonalizationNode(c)
+	graph.LinkHelper(a, b, 1.0)
+	graph.LinkHelper(c, d, 1.0)
+	graph.LinkHelper(b, e, 1.0)
+	graph.LinkHelper(b, d, 2.0)
+	graph.LinkHelper(d, e, 3.0)
+	graph.LinkHelper(e, a, 3.0)
+	return graph
+}
+
+func TestDustConvergence(t *testing.T) {
+	for _, ε := range []uint64{1, 2} {
+		graph := dustCycleGraph(ε)
+		graph.MaxIterations = 1000
+		if err := graph.TryRank(func(string, Uint, Uint) {}); err != nil {
+			t.Errorf("ε = %d units: %v", ε, err)
+		}
+	}
+}
+
+func TestNotConverged(t *testing.T) {
+	graph := dustCycleGraph(1)
+	graph.MaxIterations = 10
+	err := graph.TryRank(func(string, Uint, Uint) {})
+	if notConverged, ok := err.(NotConvergedError); !ok || notConverged.Iterations != 10 {
+		t.Errorf("expected a NotConvergedError after 10 iterations, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package detrep
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNotRanked is returned by queries that need the results of Rank
+var ErrNotRanked = errors.New("graph has not been ranked")
+
+// Contribution is the rank a node receives through a link from one of its in-neighbours
+type Contribution struct {
+	Source string // id of the in-neighbour
+	Weight Uint   // normalized weight of the link
+	Rank   Uint   // α · rank of the source · weight
+}
+
+// Breakdown splits the rank of a positive or negative node into its sources
+type Breakdown struct {
+	Rank          Uint
+	Contributions []Contribution // sorted by rank, highest first
+	Teleport      Uint           // rank from random jumps and dangling nodes
+	Residual      Int            // rank not accounted for by contributions and teleport (convergence and rounding error)
+}
+
+// Explanation describes where the ranks of a node come from
+type Explanation struct {
+	ID       string
+	Positive Breakdown // PRank, from positive links
+	Negative Breakdown // NRank, from negative links
+	// NegConsumerWeight is the share of the node's outgoing weight taken by the negConsumer link
+	// 0 if the node had no negative rank in the previous computation
+	NegConsumerWeight Uint
+	// NegConsumerRank is the rank the node sent to the negConsumer instead of the nodes it links to
+	NegConsumerRank Uint
+}
+
+// Explain decomposes the ranks of a node into contributions from its in-neighbours and teleport
+// it must be called after Rank
+// contributions are computed from the converged ranks, so they add up to the node's rank within ε
+func (graph *Graph) Explain(id string) (Explanation, error) {
+	state := graph.rankState
+	if state == nil {
+		return Explanation{}, ErrNotRanked
+	}
+
+	posNode, isPos := graph.Nodes[id]
+	negNode, isNeg := graph.NegNodes[getKey(id, Negative)]
+	if !isPos && !isNeg {
+		return Explanation{}, fmt.Errorf("node %s not found", id)
+	}
+
+	empty := Breakdown{
+		Rank:          ZeroUint(),
+		Contributions: []Contribution{},
+		Teleport:      ZeroUint(),
+		Residual:      ZeroInt(),
+	}
+	explanation := Explanation{
+		ID:                id,
+		Positive:          empty,
+		Negative:          empty,
+		NegConsumerWeight: ZeroUint(),
+		NegConsumerRank:   ZeroUint(),
+	}
+	if isPos {
+		explanation.Positive = graph.breakdown(id, posNode.PRank, graph.teleport(id, Positive))
+	}
+	if isNeg {
+		explanation.Negative = graph.breakdown(getKey(id, Negative), negNode.PRank, graph.teleport(id, Negative))
+	}
+
+	if isPos {
+		if weight, ok := graph.Edges[id][graph.NegConsumer.ID]; ok {
+			explanation.NegConsumerWeight = weight
+			explanation.NegConsumerRank = graph.contribution(posNode.PRank, weight)
+		}
+	}
+
+	return explanation, nil
+}
+
+// contribution is the rank sent through a normalized link, computed the same way as in Rank
+func (graph *Graph) contribution(rank Uint, weight Uint) Uint {
+	return graph.Params.α.Mul(rank).Quo(graph.Precision).Mul(weight).Quo(graph.Precision)
+}
+
+// breakdown collects all the links pointing to key
+func (graph *Graph) breakdown(key string, rank Uint, teleport Uint) Breakdown {
+	result := Breakdown{Rank: rank, Teleport: teleport, Contributions: []Contribution{}}
+
+	total := teleport
+	for source, targets := range graph.Edges {
+		weight, ok := targets[key]
+		if !ok {
+			continue
+		}
+		contribution := Contribution{
+			Source: source,
+			Weight: weight,
+			Rank:   graph.contribution(graph.Nodes[source].PRank, weight),
+		}
+		total = total.Add(contribution.Rank)
+		result.Contributions = append(result.Contributions, contribution)
+	}
+
+	sort.Slice(result.Contributions, func(i, j int) bool {
+		a, b := result.Contributions[i], result.Contributions[j]
+		if !a.Rank.Equal(b.Rank) {
+			return a.Rank.GT(b.Rank)
+		}
+		return a.Source < b.Source
+	})
+
+	result.Residual = NewIntFromBigInt(rank.BigInt()).Sub(NewIntFromBigInt(total.BigInt()))
+	return result
+}
+
+// teleport returns the rank a node gets from random jumps, dangling nodes and rounding dust in the last iteration
+func (graph *Graph) teleport(id string, nodeType NodeType) Uint {
+	state := graph.rankState
+	one := graph.Precision
+	α := graph.Params.α
+	dangling := state.stats.DanglingWeight
+
+	dust, ok := graph.dustShares(state, state.stats.Dust)[getKey(id, nodeType)]
+	if !ok {
+		dust = ZeroUint()
+	}
+
+	if len(graph.Params.Personalization) == 0 {
+		if state.n.IsZero() {
+			return ZeroUint()
+		}
+		return one.Sub(α).Quo(state.n).Add(dangling.Quo(state.n)).Add(dust)
+	}
+
+	// only positive personalization nodes get the random jumps
+	teleport := ZeroUint()
+	if nodeType == Positive {
+		for i, root := range graph.Params.Personalization {
+			if root == id {
+				teleport = teleport.Add(one.Sub(α).Add(dangling).Mul(state.pWeights[i]).Quo(graph.Precision))
+			}
+		}
+	}
+	return teleport.Add(dust)
+}
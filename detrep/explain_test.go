@@ -0,0 +1,109 @@
+package detrep
+
+import (
+	"testing"
+)
+
+func rankTwice(links func(graph *Graph, nodes map[string]Node)) (*Graph, map[string]Ranks) {
+	ids := []string{"a", "b", "c", "d", "e"}
+	results := map[string]Ranks{}
+	getResult := func(id string) Ranks {
+		if result, ok := results[id]; ok {
+			return result
+		}
+		return Ranks{ID: id, PRank: zero, NRank: zero}
+	}
+
+	var graph *Graph
+	for pass := 0; pass < 2; pass++ {
+		graph = NewGraphHelper(0.85, 0.000001, getResult(NegConsumerID).PRank)
+		nodes := map[string]Node{}
+		for _, id := range ids {
+			nodes[id] = NewNode(id, getResult(id).PRank, getResult(id).NRank)
+		}
+		graph.AddPersonalizationNode(nodes["a"])
+		links(graph, nodes)
+
+		results = map[string]Ranks{}
+		graph.Rank(func(id string, pRank Uint, nRank Uint) {
+			results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+		})
+	}
+	return graph, results
+}
+
+func checkBreakdown(t *testing.T, graph *Graph, id string, b Breakdown) {
+	total := NewIntFromBigInt(b.Teleport.BigInt()).Add(b.Residual)
+	for _, c := range b.Contributions {
+		total = total.Add(NewIntFromBigInt(c.Rank.BigInt()))
+	}
+	if !total.Equal(NewIntFromBigInt(b.Rank.BigInt())) {
+		t.Errorf("%s: contributions %s don't add up to rank %s", id, total, b.Rank)
+	}
+	ε := NewIntFromBigInt(graph.Params.ε.BigInt())
+	if b.Residual.GT(ε) || b.Residual.Neg().GT(ε) {
+		t.Errorf("%s: residual %s should be within ε", id, b.Residual)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	graph, results := rankTwice(func(graph *Graph, n map[string]Node) {
+		graph.LinkHelper(n["a"], n["b"], 1.0)
+		graph.LinkHelper(n["a"], n["c"], 2.0)
+		graph.LinkHelper(n["c"], n["d"], 1.0)
+		graph.LinkHelper(n["b"], n["d"], -1.0)
+		graph.LinkHelper(n["d"], n["e"], 1.0)
+	})
+
+	for _, id := range []string{"a", "b", "c", "d", "e", NegConsumerID} {
+		explanation, err := graph.Explain(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !explanation.Positive.Rank.Equal(results[id].PRank) {
+			t.Errorf("%s: explanation pRank doesn't match results", id)
+		}
+		if results[id].NRank.IsZero() != explanation.Negative.Rank.IsZero() {
+			t.Errorf("%s: explanation nRank doesn't match results", id)
+		}
+		checkBreakdown(t, graph, id, explanation.Positive)
+		checkBreakdown(t, graph, id, explanation.Negative)
+	}
+
+	a, _ := graph.Explain("a")
+	if a.Positive.Teleport.IsZero() || len(a.Positive.Contributions) != 0 {
+		t.Errorf("personalization node rank should only come from teleport")
+	}
+
+	d, _ := graph.Explain("d")
+	if len(d.Positive.Contributions) != 1 || d.Positive.Contributions[0].Source != "c" {
+		t.Errorf("expected d to get positive rank from c %v", d.Positive.Contributions)
+	}
+	if len(d.Negative.Contributions) != 1 || d.Negative.Contributions[0].Source != "b" {
+		t.Errorf("expected d to get negative rank from b %v", d.Negative.Contributions)
+	}
+	if d.NegConsumerWeight.IsZero() || d.NegConsumerRank.IsZero() {
+		t.Errorf("expected the outgoing weight of d to be reduced by the negConsumer")
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	graph := NewGraphHelper(0.85, 0.000001, zero)
+	graph.LinkHelper(NewNodeInputHelper("a", 0, 0), NewNodeInputHelper("b", 0, 0), 1.0)
+
+	if _, err := graph.Explain("a"); err != ErrNotRanked {
+		t.Errorf("expected ErrNotRanked but got %v", err)
+	}
+
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {})
+
+	if _, err := graph.Explain("x"); err == nil {
+		t.Errorf("expected an error for a missing node")
+	}
+
+	a, err := graph.Explain("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBreakdown(t, graph, "a", a.Positive)
+}
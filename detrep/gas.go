@@ -0,0 +1,139 @@
+package detrep
+
+import (
+	"fmt"
+	"math"
+)
+
+// gas descriptors
+const (
+	GasDescLink      = "detrep: link"
+	GasDescFinalize  = "detrep: finalize"
+	GasDescNormalize = "detrep: normalize"
+	GasDescIteration = "detrep: iteration"
+)
+
+// Meter is charged for the work done by Link and Rank
+// it has the shape of the cosmos sdk.GasMeter so a context gas meter can be used directly
+// ConsumeGas is expected to panic when the limit is reached
+type Meter interface {
+	ConsumeGas(amount uint64, descriptor string)
+}
+
+// GasConfig is the cost of each unit of work
+type GasConfig struct {
+	Link       uint64 // per call to Link
+	Node       uint64 // per node, when finalizing and in every iteration
+	Relaxation uint64 // per edge, when normalizing and in every iteration
+	Iteration  uint64 // flat cost of every iteration
+}
+
+// DefaultGasConfig returns the default gas costs
+func DefaultGasConfig() GasConfig {
+	return GasConfig{
+		Link:       100,
+		Node:       10,
+		Relaxation: 20,
+		Iteration:  1000,
+	}
+}
+
+// Estimate returns an upper bound of the gas used to Link the given number of links
+// and Rank the resulting graph for the given number of iterations
+// ids is the number of distinct node ids, each of them can have a positive and a negative node
+func (config GasConfig) Estimate(ids, links, iterations uint64) uint64 {
+	// negative nodes and the negConsumer
+	nodes := add(mul(ids, 2), 1)
+	// each positive node can get an extra link to the negConsumer
+	edges := add(links, ids)
+
+	gas := mul(links, config.Link)
+	gas = add(gas, mul(nodes, config.Node))
+	gas = add(gas, mul(edges, config.Relaxation))
+	return add(gas, mul(iterations, config.iterationCost(nodes, edges)))
+}
+
+// iterationCost is the gas charged by a single iteration
+func (config GasConfig) iterationCost(nodes, edges uint64) uint64 {
+	return add(config.Iteration, add(mul(nodes, config.Node), mul(edges, config.Relaxation)))
+}
+
+// OutOfGasError is the panic value of GasMeter when it runs out of gas
+type OutOfGasError struct {
+	Descriptor string
+}
+
+func (e OutOfGasError) Error() string {
+	return fmt.Sprintf("out of gas in location: %s", e.Descriptor)
+}
+
+// GasMeter is a Meter with a limit
+type GasMeter struct {
+	Limit    uint64
+	Consumed uint64
+}
+
+// NewGasMeter returns a meter that panics with an OutOfGasError once more than limit is consumed
+func NewGasMeter(limit uint64) *GasMeter {
+	return &GasMeter{Limit: limit}
+}
+
+// ConsumeGas implements Meter
+func (meter *GasMeter) ConsumeGas(amount uint64, descriptor string) {
+	meter.Consumed = add(meter.Consumed, amount)
+	if meter.Consumed > meter.Limit {
+		panic(OutOfGasError{Descriptor: descriptor})
+	}
+}
+
+// TryRank is Rank but returns an OutOfGasError instead of panicking when the meter runs out,
+// and a NotConvergedError when Rank doesn't converge within MaxIterations
+// other panics, including the ones from an sdk.GasMeter, are not recovered
+// the graph should not be used after an error
+func (graph *Graph) TryRank(callback func(key string, pRank Uint, nRank Uint)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case OutOfGasError:
+				err = r
+			case NotConvergedError:
+				err = r
+			default:
+				panic(r)
+			}
+		}
+	}()
+	graph.Rank(callback)
+	return nil
+}
+
+// consumeGas charges the graph meter if there is one
+func (graph *Graph) consumeGas(amount uint64, descriptor string) {
+	if graph.Meter != nil {
+		graph.Meter.ConsumeGas(amount, descriptor)
+	}
+}
+
+// edgeCount returns the number of edges in the graph
+func (graph *Graph) edgeCount() uint64 {
+	var edges uint64
+	for _, targets := range graph.Edges {
+		edges += uint64(len(targets))
+	}
+	return edges
+}
+
+// add and mul saturate at math.MaxUint64 so estimates and meters can't overflow
+func add(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
+
+func mul(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
@@ -0,0 +1,91 @@
+package detrep
+
+import (
+	"testing"
+)
+
+func meteredGraph(meter Meter) *Graph {
+	graph := NewGraphHelper(0.85, 0.000001, zero)
+	graph.Meter = meter
+
+	a := NewNodeInputHelper("a", 0, 0)
+	b := NewNodeInputHelper("b", 0, 0)
+	c := NewNodeInputHelper("c", 0, 0)
+	d := NewNodeInputHelper("d", 0, 0)
+
+	graph.AddPersonalizationNode(a)
+	graph.LinkHelper(a, b, 1.0)
+	graph.LinkHelper(a, c, 2.0)
+	graph.LinkHelper(b, d, -1.0)
+	graph.LinkHelper(c, d, 1.0)
+	return graph
+}
+
+func TestGasMeter(t *testing.T) {
+	meter := NewGasMeter(1e9)
+	graph := meteredGraph(meter)
+
+	if meter.Consumed != 4*graph.Gas.Link {
+		t.Errorf("expected %d gas for links, got %d", 4*graph.Gas.Link, meter.Consumed)
+	}
+
+	var iterations int
+	graph.Observer = iterationCounter(func(stats IterationStats) { iterations = stats.Iteration })
+	if err := graph.TryRank(func(string, Uint, Uint) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	estimate := graph.Gas.Estimate(4, 4, uint64(iterations))
+	if meter.Consumed > estimate {
+		t.Errorf("estimate %d should be an upper bound of %d", estimate, meter.Consumed)
+	}
+
+	// the same graph always uses the same amount of gas
+	again := NewGasMeter(1e9)
+	meteredGraph(again).Rank(func(string, Uint, Uint) {})
+	if again.Consumed != meter.Consumed {
+		t.Errorf("gas should be deterministic: %d != %d", again.Consumed, meter.Consumed)
+	}
+}
+
+func TestOutOfGas(t *testing.T) {
+	full := NewGasMeter(1e9)
+	meteredGraph(full).Rank(func(string, Uint, Uint) {})
+
+	for _, limit := range []uint64{100, full.Consumed / 2, full.Consumed - 1} {
+		meter := NewGasMeter(limit)
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = r.(OutOfGasError)
+				}
+			}()
+			graph := meteredGraph(meter)
+			err = graph.TryRank(func(string, Uint, Uint) {
+				t.Errorf("results should not be returned when out of gas")
+			})
+		}()
+		if _, ok := err.(OutOfGasError); !ok {
+			t.Errorf("limit %d: expected an OutOfGasError, got %v", limit, err)
+		}
+	}
+
+	meter := NewGasMeter(full.Consumed)
+	if err := meteredGraph(meter).TryRank(func(string, Uint, Uint) {}); err != nil {
+		t.Errorf("ranking should fit in %d gas: %v", full.Consumed, err)
+	}
+}
+
+func TestEstimateSaturates(t *testing.T) {
+	config := DefaultGasConfig()
+	if config.Estimate(1<<62, 1<<62, 1<<62) != ^uint64(0) {
+		t.Errorf("estimate should saturate instead of overflowing")
+	}
+}
+
+type iterationCounter func(stats IterationStats)
+
+func (f iterationCounter) Start(*Graph)                         {}
+func (f iterationCounter) Iteration(_ *Graph, s IterationStats) { f(s) }
+func (f iterationCounter) Complete(*Graph, IterationStats)      {}
@@ -13,9 +13,8 @@
 package detrep
 
 import (
+	"fmt"
 	"strconv"
-
-	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 // NodeType is positive or negative
@@ -33,18 +32,27 @@ const (
 // Decimals is the default decimal precision used in computation
 const Decimals = 18
 
+// MaxDecimals is the highest supported decimal precision
+const MaxDecimals = 36
+
 // MaxNegOffset defines the cutoff for when a node will have it's outging links counted
 // if previously NegativeRank / PositiveRank > MaxNegOffset / (MaxNegOffset + 1) we will not consider
 // any outgoing links
 // otherwise, we counter the outgoing lings with one 'heavy' link proportional to the MaxNegOffset ratio
 const MaxNegOffset = 10
 
+// DefaultMaxIterations is the number of iterations after which Rank gives up by default
+const DefaultMaxIterations = 10000
+
+// NegConsumerID is the id of the node that consumes the outgoing weight of nodes with a negative rank
+const NegConsumerID = "negConsumer"
+
 // Node is an internal node struct
 type Node struct {
 	ID       string
-	PRank    sdk.Uint // pos page rank of the node
-	NRank    sdk.Uint // only used when combining results
-	degree   sdk.Uint // sum of all outgoing links
+	PRank    Uint // pos page rank of the node
+	NRank    Uint // only used when combining results
+	degree   Uint // sum of all outgoing links
 	nodeType NodeType
 }
 
@@ -52,11 +60,31 @@ type Node struct {
 type Graph struct {
 	Nodes        map[string]*Node
 	NegNodes     map[string]*Node
-	Edges        map[string](map[string]sdk.Uint)
+	Edges        map[string](map[string]Uint)
 	Params       RankParams
 	NegConsumer  Node
-	Precision    sdk.Uint
-	MaxNegOffset sdk.Uint
+	Decimals     int       // decimal precision of ranks and params
+	Precision    Uint      // 10^Decimals, the rank of all nodes adds up to Precision
+	MaxNegOffset Uint      // MaxNegOffset with Decimals
+	Observer     Observer  // optional, notified as Rank progresses
+	Meter        Meter     // optional, charged for Link and Rank
+	Gas          GasConfig // costs charged to the Meter
+
+	// MaxIterations bounds the iterations of Rank, DefaultMaxIterations by default
+	// Rank panics with a NotConvergedError (returned by TryRank) if Δ is still above ε after them
+	MaxIterations int
+
+	rankState *rankState // set once Rank completes
+}
+
+// rankState holds the values used in the last iteration of Rank
+type rankState struct {
+	n            Uint     // number of nodes, including negative ones
+	pWeights     []Uint   // personalization weights
+	keys         []string // sorted node keys
+	stats        IterationStats
+	roundingLoss Uint          // rank lost to rounding over all iterations
+	acc          *accumulators // indexed graph and buffers used by iterate
 }
 
 // RankParams is the pagerank parameters
@@ -64,29 +92,54 @@ type Graph struct {
 // ε is the min global error between iterations
 // personalization is the personalization vector (can be nil for non-personalized pr)
 type RankParams struct {
-	α, ε            sdk.Uint
+	α, ε            Uint
 	Personalization []string
 }
 
+// Option configures a graph
+type Option func(graph *Graph)
+
+// WithDecimals sets the decimal precision of a graph, Decimals by default
+// α, ε and the cached ranks must use the same precision
+// fewer decimals are cheaper to compute with but less accurate.
+// ε can't be smaller than the rounding error of an iteration, see MinEpsilon:
+// with 6 decimals an ε of 1e-6 is a single unit and Rank may not converge
+func WithDecimals(decimals int) Option {
+	if decimals < 1 || decimals > MaxDecimals {
+		panic(fmt.Sprintf("detrep: decimals must be between 1 and %d, got %d", MaxDecimals, decimals))
+	}
+	return func(graph *Graph) {
+		graph.Decimals = decimals
+		graph.Precision = pow10(1, decimals)
+		graph.MaxNegOffset = pow10(MaxNegOffset, decimals)
+	}
+}
+
 // NewGraph initializes and returns a new graph.
-func NewGraph(α sdk.Uint, ε sdk.Uint, negConsumerRank sdk.Uint) *Graph {
-	return &Graph{
+func NewGraph(α Uint, ε Uint, negConsumerRank Uint, options ...Option) *Graph {
+	graph := &Graph{
 		Nodes:    make(map[string]*Node),
 		NegNodes: make(map[string]*Node),
-		Edges:    make(map[string](map[string]sdk.Uint)),
+		Edges:    make(map[string](map[string]Uint)),
 		Params: RankParams{
 			α:               α,
 			ε:               ε,
 			Personalization: make([]string, 0),
 		},
-		NegConsumer:  Node{ID: "negConsumer", PRank: negConsumerRank, NRank: sdk.ZeroUint()},
-		Precision:    sdk.NewUintFromBigInt(sdk.NewIntWithDecimal(1, Decimals).BigInt()),
-		MaxNegOffset: sdk.NewUintFromBigInt(sdk.NewIntWithDecimal(MaxNegOffset, Decimals).BigInt()),
+		NegConsumer: Node{ID: NegConsumerID, PRank: negConsumerRank, NRank: ZeroUint()},
+		Gas:         DefaultGasConfig(),
+
+		MaxIterations: DefaultMaxIterations,
+	}
+	WithDecimals(Decimals)(graph)
+	for _, option := range options {
+		option(graph)
 	}
+	return graph
 }
 
 // NewNode is ahelper method to create a node input struct
-func NewNode(id string, pRank sdk.Uint, nRank sdk.Uint) Node {
+func NewNode(id string, pRank Uint, nRank Uint) Node {
 	return Node{ID: id, PRank: pRank, NRank: nRank}
 }
 
@@ -101,10 +154,11 @@ func (graph *Graph) AddPersonalizationNode(pNode Node) {
 
 // Link creates a weighted edge between a source-target node pair.
 // If the edge already exists, the weight is incremented.
-func (graph *Graph) Link(source, target Node, weight sdk.Int) {
+func (graph *Graph) Link(source, target Node, weight Int) {
+	graph.consumeGas(graph.Gas.Link, GasDescLink)
 
 	// if a node's neg/pos rank is > MaxNegOffset / (MaxNegOffset + 1) we don't process it
-	if source.PRank.GT(sdk.ZeroUint()) {
+	if source.PRank.GT(ZeroUint()) {
 		negPosRatio := source.NRank.Mul(graph.Precision).Quo(source.PRank)
 		one := graph.Precision
 		if negPosRatio.GT(graph.MaxNegOffset.Mul(graph.Precision).Quo(graph.MaxNegOffset.Add(one))) {
@@ -117,13 +171,13 @@ func (graph *Graph) Link(source, target Node, weight sdk.Int) {
 
 	// if weight is negative we use negative receiving node
 	var nodeType NodeType
-	var weightUint sdk.Uint
-	if weight.LT(sdk.ZeroInt()) {
+	var weightUint Uint
+	if weight.LT(ZeroInt()) {
 		nodeType = Negative
-		weightUint = sdk.NewUintFromBigInt(weight.Neg().BigInt())
+		weightUint = NewUintFromBigInt(weight.Neg().BigInt())
 	} else {
 		nodeType = Positive
-		weightUint = sdk.NewUintFromBigInt(weight.BigInt())
+		weightUint = NewUintFromBigInt(weight.BigInt())
 	}
 	targetKey := getKey(target.ID, nodeType)
 
@@ -132,21 +186,22 @@ func (graph *Graph) Link(source, target Node, weight sdk.Int) {
 	sourceNode.degree = sourceNode.degree.Add(weightUint)
 
 	if _, ok := graph.Edges[sourceKey]; ok == false {
-		graph.Edges[sourceKey] = map[string]sdk.Uint{}
+		graph.Edges[sourceKey] = map[string]Uint{}
 	}
 
 	if _, ok := graph.Edges[sourceKey][targetKey]; ok == false {
-		graph.Edges[sourceKey][targetKey] = sdk.ZeroUint()
+		graph.Edges[sourceKey][targetKey] = ZeroUint()
 	}
 
 	graph.Edges[sourceKey][targetKey] = graph.Edges[sourceKey][targetKey].Add(weightUint)
 
 	// note: use target.id here to make sure we reference the original id
-	graph.cancelOpposites(*sourceNode, target.ID, nodeType)
+	graph.cancelOpposites(sourceNode, target.ID, nodeType)
 }
 
 // Finalize is the method that runs after all other inits and before pagerank
 func (graph *Graph) Finalize() {
+	graph.consumeGas(mul(uint64(len(graph.NegNodes)), graph.Gas.Node), GasDescFinalize)
 	graph.processNegatives()
 }
 
@@ -178,7 +233,7 @@ func (graph *Graph) processNegatives() {
 		// posNode.rank is not 0 check above
 		negPosRatio := negNode.PRank.Mul(graph.Precision).Quo(posNode.PRank)
 
-		var negMultiple sdk.Uint
+		var negMultiple Uint
 
 		// if negPosRatio > MaxNegOffset / (MaxNegOffset + 1) we use the MaxNegOffset
 		// this first case should not happen because we ignore these links
@@ -193,11 +248,11 @@ func (graph *Graph) processNegatives() {
 
 		// this should actually never happen if degree is > 0
 		if _, ok := graph.Edges[negNode.ID]; ok == false {
-			graph.Edges[negNode.ID] = map[string]sdk.Uint{}
+			graph.Edges[negNode.ID] = map[string]Uint{}
 		}
 
 		if _, ok := graph.Edges[negNode.ID][negConsumer.ID]; ok == false {
-			graph.Edges[negNode.ID][negConsumer.ID] = sdk.ZeroUint()
+			graph.Edges[negNode.ID][negConsumer.ID] = ZeroUint()
 		}
 
 		graph.Edges[negNode.ID][negConsumer.ID] = graph.Edges[negNode.ID][negConsumer.ID].Add(negWeight)
@@ -206,7 +261,7 @@ func (graph *Graph) processNegatives() {
 }
 
 // if there is both a positive and a negative link from A to B we cancel them out
-func (graph *Graph) cancelOpposites(sourceNode Node, target string, nodeType NodeType) {
+func (graph *Graph) cancelOpposites(sourceNode *Node, target string, nodeType NodeType) {
 	key := getKey(target, nodeType)
 	var oppositeKey string
 	if oppositeKey = getKey(target, Positive); nodeType == Positive {
@@ -225,19 +280,19 @@ func (graph *Graph) cancelOpposites(sourceNode Node, target string, nodeType Nod
 		graph.removeEdge(sourceNode.ID, key)
 		graph.Edges[sourceNode.ID][oppositeKey] = opositeEdge.Sub(edge)
 		// remove degree from both delete node and the adjustment
-		sourceNode.degree = sourceNode.degree.Sub(edge.Mul(sdk.NewUint(2)))
+		sourceNode.degree = sourceNode.degree.Sub(edge.Mul(NewUint(2)))
 
 	case edge.GT(opositeEdge):
 		graph.removeEdge(sourceNode.ID, oppositeKey)
 		graph.Edges[sourceNode.ID][key] = edge.Sub(opositeEdge)
 		// remove degree from both delete node and the adjustment
-		sourceNode.degree = sourceNode.degree.Sub(opositeEdge.Mul(sdk.NewUint(2)))
+		sourceNode.degree = sourceNode.degree.Sub(opositeEdge.Mul(NewUint(2)))
 
 	case edge.Equal(opositeEdge):
 		graph.removeEdge(sourceNode.ID, oppositeKey)
 		graph.removeEdge(sourceNode.ID, key)
 
-		sourceNode.degree = sourceNode.degree.Sub(opositeEdge.Mul(sdk.NewUint(2)))
+		sourceNode.degree = sourceNode.degree.Sub(opositeEdge.Mul(NewUint(2)))
 	}
 }
 
@@ -251,9 +306,9 @@ func (graph *Graph) initNode(key string, inputNode Node, nodeType NodeType) *Nod
 	if _, ok := graph.Nodes[key]; ok == false {
 		graph.Nodes[key] = &Node{
 			ID:       inputNode.ID, // id is independent of pos/neg keys
-			degree:   sdk.ZeroUint(),
-			PRank:    sdk.ZeroUint(),
-			NRank:    sdk.ZeroUint(),
+			degree:   ZeroUint(),
+			PRank:    ZeroUint(),
+			NRank:    ZeroUint(),
 			nodeType: nodeType,
 		}
 		// store negative nodes so we can easily merge them later
@@ -262,7 +317,7 @@ func (graph *Graph) initNode(key string, inputNode Node, nodeType NodeType) *Nod
 		}
 	}
 	// update rank here in case we initilized with 0 early on
-	var prevRank sdk.Uint
+	var prevRank Uint
 	if prevRank = inputNode.PRank; nodeType == Negative {
 		prevRank = inputNode.NRank
 	}
@@ -0,0 +1,139 @@
+package detrep
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash"
+	"sort"
+
+	"github.com/relevant-community/reputation/cache"
+)
+
+// ErrRanked is returned by methods that need a graph that has not been ranked yet
+var ErrRanked = errors.New("graph has already been ranked")
+
+// ErrUncacheable is returned by RankCached for graphs with a Meter or an Observer,
+// cached results would skip the gas and the iterations of Rank
+var ErrUncacheable = errors.New("graphs with a gas meter or an observer can't use cached results")
+
+// hashPrefix separates detrep graph hashes from rep ones
+const hashPrefix = "detrep/graph/v1"
+
+// Hash returns a canonical hash of the graph inputs:
+// params, precision, the cached rank of the negConsumer, the personalization vector,
+// the nodes with their cached ranks and the edges, in sorted order
+// graphs built with the same links in any order have the same hash, but the personalization
+// is hashed in the order it was added since ties in the dust shares go to the first nodes
+// it must be called before Rank, which modifies the graph
+func (graph *Graph) Hash() ([]byte, error) {
+	if graph.rankState != nil {
+		return nil, ErrRanked
+	}
+
+	h := sha256.New()
+	h.Write([]byte(hashPrefix))
+	writeUint(h, graph.Params.α)
+	writeUint(h, graph.Params.ε)
+	writeUint(h, graph.Precision)
+	writeUint(h, graph.MaxNegOffset)
+	writeUint(h, graph.NegConsumer.PRank)
+
+	writeLength(h, len(graph.Params.Personalization))
+	for _, id := range graph.Params.Personalization {
+		writeString(h, id)
+	}
+
+	keys := make([]string, 0, len(graph.Nodes))
+	for key := range graph.Nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	writeLength(h, len(keys))
+	for _, key := range keys {
+		node := graph.Nodes[key]
+		writeString(h, key)
+		writeString(h, node.ID)
+		writeLength(h, int(node.nodeType))
+		writeUint(h, node.PRank)
+	}
+
+	sources := make([]string, 0, len(graph.Edges))
+	for source := range graph.Edges {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	writeLength(h, len(sources))
+	for _, source := range sources {
+		targets := make([]string, 0, len(graph.Edges[source]))
+		for target := range graph.Edges[source] {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		writeString(h, source)
+		writeLength(h, len(targets))
+		for _, target := range targets {
+			writeString(h, target)
+			writeUint(h, graph.Edges[source][target])
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// RankCached is Rank but returns the results cached for the graph hash if there are any
+// it returns true if the results came from the cache, in which case the graph is not ranked
+// results are passed to the callback in id order
+// graphs with a Meter or an Observer return ErrUncacheable, use Rank or TryRank for them
+func (graph *Graph) RankCached(c cache.Cache, callback func(id string, pRank Uint, nRank Uint)) (bool, error) {
+	if graph.Meter != nil || graph.Observer != nil {
+		return false, ErrUncacheable
+	}
+	key, err := graph.Hash()
+	if err != nil {
+		return false, err
+	}
+
+	bz, ok, err := c.Get(key)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		var results []Ranks
+		if err := json.Unmarshal(bz, &results); err != nil {
+			return false, err
+		}
+		for _, result := range results {
+			callback(result.ID, result.PRank, result.NRank)
+		}
+		return true, nil
+	}
+
+	results := []Ranks{}
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
+		results = append(results, Ranks{ID: id, PRank: pRank, NRank: nRank})
+		callback(id, pRank, nRank)
+	})
+
+	bz, err = json.Marshal(results)
+	if err != nil {
+		return false, err
+	}
+	return false, c.Set(key, bz)
+}
+
+func writeLength(h hash.Hash, n int) {
+	var bz [binary.MaxVarintLen64]byte
+	h.Write(bz[:binary.PutUvarint(bz[:], uint64(n))])
+}
+
+func writeString(h hash.Hash, s string) {
+	writeLength(h, len(s))
+	h.Write([]byte(s))
+}
+
+func writeUint(h hash.Hash, u Uint) {
+	bz := uintBytes(u)
+	writeLength(h, len(bz))
+	h.Write(bz)
+}
@@ -0,0 +1,146 @@
+package detrep
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/relevant-community/reputation/cache"
+)
+
+// reversedLinks adds the links of verifyLinks in reverse order
+func reversedLinks(graph *Graph, n map[string]Node) {
+	graph.LinkHelper(n["d"], n["e"], 3.0)
+	graph.LinkHelper(n["c"], n["d"], -1.0)
+	graph.LinkHelper(n["b"], n["a"], 1.0)
+	graph.LinkHelper(n["b"], n["c"], -1.0)
+	graph.LinkHelper(n["a"], n["c"], 1.0)
+	graph.LinkHelper(n["a"], n["b"], 2.0)
+}
+
+func mustHash(t *testing.T, graph *Graph) []byte {
+	hash, err := graph.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestHash(t *testing.T) {
+	_, results := rankTwice(verifyLinks)
+	hash := mustHash(t, unrankedGraph(results, verifyLinks))
+
+	if !bytes.Equal(hash, mustHash(t, unrankedGraph(results, reversedLinks))) {
+		t.Error("link order should not change the hash")
+	}
+
+	changes := map[string]func(graph *Graph){
+		"weight":      func(graph *Graph) { graph.Link(NewNode("a", zero, zero), NewNode("b", zero, zero), NewInt(1)) },
+		"rank":        func(graph *Graph) { graph.Nodes["c"].PRank = graph.Nodes["c"].PRank.Add(OneUint()) },
+		"seed":        func(graph *Graph) { graph.Params.Personalization = append(graph.Params.Personalization, "b") },
+		"alpha":       func(graph *Graph) { graph.Params.α = FtoBD(0.8) },
+		"negConsumer": func(graph *Graph) { graph.NegConsumer.PRank = graph.NegConsumer.PRank.Add(OneUint()) },
+	}
+	for name, change := range changes {
+		graph := unrankedGraph(results, verifyLinks)
+		change(graph)
+		if bytes.Equal(hash, mustHash(t, graph)) {
+			t.Errorf("%s: the hash should change", name)
+		}
+	}
+
+	graph := unrankedGraph(results, verifyLinks)
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {})
+	if _, err := graph.Hash(); err != ErrRanked {
+		t.Errorf("expected ErrRanked, got %v", err)
+	}
+}
+
+func TestHashPersonalizationOrder(t *testing.T) {
+	// the dust is split between a and c, ties go to the first one
+	build := func(seeds ...string) *Graph {
+		graph := NewGraphHelper(0.85, 0.000001, zero)
+		for _, seed := range seeds {
+			graph.AddPersonalizationNode(NewNodeInputHelper(seed, 0, 0))
+		}
+		graph.LinkHelper(NewNodeInputHelper("c", 0, 0), NewNodeInputHelper("b", 0, 0), 3.0)
+		graph.LinkHelper(NewNodeInputHelper("b", 0, 0), NewNodeInputHelper("d", 0, 0), 1.0)
+		return graph
+	}
+	ac, ca := build("a", "c"), build("c", "a")
+	hashAC, hashCA := mustHash(t, ac), mustHash(t, ca)
+	commitmentAC, commitmentCA := Commitment(rankResults(ac)), Commitment(rankResults(ca))
+
+	// the same hash must mean the same results, or RankCached returns results a fresh Rank disagrees with
+	if bytes.Equal(commitmentAC, commitmentCA) {
+		t.Fatal("expected the personalization order to change the results of this graph")
+	}
+	if bytes.Equal(hashAC, hashCA) {
+		t.Error("graphs with different results should have different hashes")
+	}
+}
+
+func TestRankCachedMetered(t *testing.T) {
+	_, results := rankTwice(verifyLinks)
+	c := cache.NewMemory(0)
+
+	metered := unrankedGraph(results, verifyLinks)
+	metered.Meter = NewGasMeter(1e9)
+	observed := unrankedGraph(results, verifyLinks)
+	observed.Observer = iterationCounter(func(stats IterationStats) {})
+	for _, graph := range []*Graph{metered, observed} {
+		if _, err := graph.RankCached(c, func(id string, pRank Uint, nRank Uint) {}); err != ErrUncacheable {
+			t.Errorf("expected ErrUncacheable, got %v", err)
+		}
+	}
+	if c.Len() != 0 {
+		t.Error("nothing should be cached")
+	}
+}
+
+func TestRankCached(t *testing.T) {
+	dir, err := ioutil.TempDir("", "detrep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, results := rankTwice(verifyLinks)
+	for _, newCache := range []func() (cache.Cache, error){
+		func() (cache.Cache, error) { return cache.NewMemory(0), nil },
+		func() (cache.Cache, error) { return cache.NewFile(dir) },
+	} {
+		c, err := newCache()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rank := func(graph *Graph) (map[string]Ranks, bool) {
+			ranked := map[string]Ranks{}
+			hit, err := graph.RankCached(c, func(id string, pRank Uint, nRank Uint) {
+				ranked[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return ranked, hit
+		}
+
+		first, hit := rank(unrankedGraph(results, verifyLinks))
+		if hit {
+			t.Error("the first computation should not come from the cache")
+		}
+
+		graph := unrankedGraph(results, reversedLinks)
+		second, hit := rank(graph)
+		if !hit {
+			t.Error("the same graph should come from the cache")
+		}
+		if graph.rankState != nil {
+			t.Error("the graph should not be ranked on a cache hit")
+		}
+		if !bytes.Equal(Commitment(first), Commitment(second)) {
+			t.Errorf("cached results differ: %v %v", first, second)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package detrep
+
+import (
+	"math/big"
+	"sort"
+)
+
+// the iterations of Rank work on indexed nodes and preallocated big.Int accumulators
+// instead of maps of immutable Uints, so they don't allocate per node or per edge.
+// every value is computed with the same operations and rounding as the Uint version,
+// so the results are identical.
+//
+// bounds: ranks, α and normalized edge weights are at most Precision <= 10^36 < 2^120,
+// so the products are below 2^240. accumulators are still checked against the Uint range
+// and panic like Uint would, in case a caller passes larger values (ex. α > Precision).
+
+// indexedEdge is a normalized edge between two node indices
+type indexedEdge struct {
+	target int
+	weight *big.Int
+}
+
+// accumulators holds the indexed graph and the buffers reused by every iteration
+type accumulators struct {
+	index    map[string]int  // position of each key in rankState.keys
+	edges    [][]indexedEdge // outgoing edges of each node, sorted by target
+	dangling []bool          // nodes without outgoing links
+	pIndex   []int           // index of each personalization node
+
+	ranks []big.Int // ranks of the current iteration
+	prev  []big.Int // ranks of the previous iteration
+	tmp   big.Int
+	rem   big.Int
+	share big.Int
+	sum   big.Int
+}
+
+// newAccumulators indexes the graph once its edges are normalized
+func newAccumulators(graph *Graph, keys []string) *accumulators {
+	acc := &accumulators{
+		index:    make(map[string]int, len(keys)),
+		edges:    make([][]indexedEdge, len(keys)),
+		dangling: make([]bool, len(keys)),
+		pIndex:   make([]int, len(graph.Params.Personalization)),
+		ranks:    make([]big.Int, len(keys)),
+		prev:     make([]big.Int, len(keys)),
+	}
+	for i, key := range keys {
+		acc.index[key] = i
+		acc.dangling[i] = graph.Nodes[key].degree.IsZero()
+	}
+	for i, key := range keys {
+		for target, weight := range graph.Edges[key] {
+			acc.edges[i] = append(acc.edges[i], indexedEdge{target: acc.index[target], weight: weight.i})
+		}
+		edges := acc.edges[i]
+		sort.Slice(edges, func(a, b int) bool { return edges[a].target < edges[b].target })
+	}
+	for i, key := range graph.Params.Personalization {
+		acc.pIndex[i] = acc.index[key]
+	}
+	return acc
+}
+
+// load copies the ranks of the graph nodes into the accumulators
+func (acc *accumulators) load(graph *Graph, keys []string) {
+	for i, key := range keys {
+		acc.ranks[i].Set(graph.Nodes[key].PRank.i)
+	}
+}
+
+// store writes the ranks of the accumulators back to the graph nodes
+func (acc *accumulators) store(graph *Graph, keys []string) {
+	for i, key := range keys {
+		graph.Nodes[key].PRank = NewUintFromBigInt(&acc.ranks[i])
+	}
+}
+
+// rank returns the current rank of key, 0 if it's not part of the graph
+func (acc *accumulators) rank(key string) Uint {
+	if i, ok := acc.index[key]; ok {
+		return NewUintFromBigInt(&acc.ranks[i])
+	}
+	return ZeroUint()
+}
+
+// addTo adds x to acc and panics like Uint.Add if the result overflows
+func addTo(acc, x *big.Int) {
+	if acc.Add(acc, x).BitLen() > maxBitLen {
+		panic("Uint overflow")
+	}
+}
+
+// mulQuo sets z = x * y / d and panics like Uint.Mul if the product overflows
+// r holds the remainder, Quo would allocate it
+func mulQuo(z, r, x, y, d *big.Int) *big.Int {
+	if z.Mul(x, y).BitLen() > maxBitLen {
+		panic("Uint overflow")
+	}
+	z.QuoRem(z, d, r)
+	return z
+}
+
+// addDust gives back rank lost to rounding in an iteration
+// the shares are the same as the ones of dustShares
+func (acc *accumulators) addDust(state *rankState, dust Uint) {
+	if dust.IsZero() {
+		return
+	}
+	if len(acc.pIndex) > 0 {
+		for i, share := range largestRemainder(dust, state.pWeights) {
+			addTo(&acc.ranks[acc.pIndex[i]], share.i)
+		}
+		return
+	}
+	if len(acc.ranks) == 0 {
+		return
+	}
+	// every node has the same weight so the remainder goes to the first keys
+	share, remainder := dust.Quo(state.n), dust.Mod(state.n).Uint64()
+	extra := share.Add(OneUint())
+	for i := range acc.ranks {
+		if uint64(i) < remainder {
+			addTo(&acc.ranks[i], extra.i)
+		} else {
+			addTo(&acc.ranks[i], share.i)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package detrep
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// randomLinks returns a reproducible graph with n ids and about degree links per id
+// a tenth of the links are negative
+func randomLinks(n, degree int, seed int64) [][3]int {
+	r := rand.New(rand.NewSource(seed))
+	links := make([][3]int, 0, n*degree)
+	for i := 0; i < n*degree; i++ {
+		weight := 1 + r.Intn(10)
+		if r.Intn(10) == 0 {
+			weight = -weight
+		}
+		links = append(links, [3]int{r.Intn(n), r.Intn(n), weight})
+	}
+	return links
+}
+
+// randomGraph builds a graph from randomLinks, using the cached ranks from results
+func randomGraph(links [][3]int, personalized bool, results map[string]Ranks) *Graph {
+	var negConsumerRank = ZeroUint()
+	if r, ok := results[NegConsumerID]; ok {
+		negConsumerRank = r.PRank
+	}
+	graph := NewGraphHelper(0.85, 0.000001, negConsumerRank)
+	node := func(i int) Node {
+		id := fmt.Sprintf("n%d", i)
+		if r, ok := results[id]; ok {
+			return NewNode(id, r.PRank, r.NRank)
+		}
+		return NewNode(id, ZeroUint(), ZeroUint())
+	}
+	if personalized {
+		graph.AddPersonalizationNode(node(0))
+		graph.AddPersonalizationNode(node(1))
+	}
+	for _, link := range links {
+		if link[0] == link[1] {
+			continue
+		}
+		graph.LinkHelper(node(link[0]), node(link[1]), float64(link[2]))
+	}
+	return graph
+}
+
+func benchmarkRank(b *testing.B, n, degree int, personalized bool) {
+	links := randomLinks(n, degree, 1)
+	// rank once so the benchmark includes the negConsumer
+	cached := rankResults(randomGraph(links, personalized, nil))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		graph := randomGraph(links, personalized, cached)
+		b.StartTimer()
+		graph.Rank(func(string, Uint, Uint) {})
+	}
+}
+
+func BenchmarkRank100(b *testing.B)              { benchmarkRank(b, 100, 5, false) }
+func BenchmarkRank1000(b *testing.B)             { benchmarkRank(b, 1000, 5, false) }
+func BenchmarkRankPersonalized1000(b *testing.B) { benchmarkRank(b, 1000, 5, true) }
+func BenchmarkRankPersonalized5000(b *testing.B) { benchmarkRank(b, 5000, 10, true) }
+
+// BenchmarkIteration runs single iterations on a prepared graph
+// once the accumulators have grown, the number of allocations doesn't depend on the size of the graph
+func BenchmarkIteration(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			graph := randomGraph(randomLinks(n, 5, 1), true, nil)
+			state, _ := graph.prepare()
+			graph.initScores(state)
+			state.acc.load(graph, state.keys)
+			for i := 0; i < 50; i++ {
+				graph.iterate(state)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				graph.iterate(state)
+			}
+		})
+	}
+}
+
+// referenceIterate is the map based iteration that iterate replaced
+func (graph *Graph) referenceIterate(state *rankState) (Uint, Uint, Uint) {
+	N := state.n
+	one := graph.Precision
+	α := graph.Params.α
+	pVector := graph.Params.Personalization
+	personalized := len(pVector) > 0
+
+	danglingWeight := ZeroUint()
+	nodes := map[string]Uint{}
+	for key, value := range graph.Nodes {
+		nodes[key] = value.PRank
+		if value.degree.IsZero() {
+			danglingWeight = danglingWeight.Add(value.PRank)
+		}
+		graph.Nodes[key].PRank = ZeroUint()
+	}
+	danglingWeight = danglingWeight.Mul(α).Quo(graph.Precision)
+
+	for source := range graph.Nodes {
+		for target, weight := range graph.Edges[source] {
+			graph.Nodes[target].PRank = graph.Nodes[target].PRank.Add(graph.contribution(nodes[source], weight))
+		}
+		if !personalized {
+			graph.Nodes[source].PRank = graph.Nodes[source].PRank.Add(one.Sub(α).Quo(N).Add(danglingWeight.Quo(N)))
+		}
+	}
+	if personalized {
+		for i, root := range pVector {
+			graph.Nodes[root].PRank = graph.Nodes[root].PRank.Add((one.Sub(α).Add(danglingWeight)).Mul(state.pWeights[i]).Quo(graph.Precision))
+		}
+	}
+
+	dust := graph.Precision.Sub(MinUint(graph.Precision, graph.totalRank()))
+
+	// the dust doesn't count in Δ
+	Δ := ZeroUint()
+	for key, value := range graph.Nodes {
+		Δ = Δ.Add(absDiff(value.PRank, nodes[key]))
+	}
+	Δ = Δ.Sub(MinUint(Δ, dust))
+
+	graph.addDust(state, dust)
+	return Δ, danglingWeight, dust
+}
+
+func TestIterateMatchesReference(t *testing.T) {
+	for _, personalized := range []bool{false, true} {
+		for seed := int64(1); seed <= 5; seed++ {
+			links := randomLinks(50, 4, seed)
+			// the second pass has cached ranks and a negConsumer
+			for pass, cached := range []map[string]Ranks{nil, rankResults(randomGraph(links, personalized, nil))} {
+				actual := randomGraph(links, personalized, cached)
+				expected := randomGraph(links, personalized, cached)
+				aState, _ := actual.prepare()
+				eState, _ := expected.prepare()
+				actual.initScores(aState)
+				expected.initScores(eState)
+				aState.acc.load(actual, aState.keys)
+
+				for i := 0; i < 20; i++ {
+					aΔ, aDangling, aDust := actual.iterate(aState)
+					eΔ, eDangling, eDust := expected.referenceIterate(eState)
+					aState.acc.store(actual, aState.keys)
+
+					name := fmt.Sprintf("personalized %v seed %d pass %d iteration %d", personalized, seed, pass, i)
+					if !aΔ.Equal(eΔ) || !aDangling.Equal(eDangling) || !aDust.Equal(eDust) {
+						t.Fatalf("%s: expected Δ %s dangling %s dust %s, got %s %s %s", name, eΔ, eDangling, eDust, aΔ, aDangling, aDust)
+					}
+					for key, node := range expected.Nodes {
+						if !actual.Nodes[key].PRank.Equal(node.PRank) {
+							t.Fatalf("%s: %s expected %s got %s", name, key, node.PRank, actual.Nodes[key].PRank)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestIterateOverflow(t *testing.T) {
+	graph := randomGraph(randomLinks(10, 2, 1), false, nil)
+	state, _ := graph.prepare()
+	graph.initScores(state)
+	state.acc.load(graph, state.keys)
+
+	// an α larger than Precision makes the products overflow, like they would with Uint
+	graph.Params.α = NewUintFromBigInt(new(big.Int).Lsh(big.NewInt(1), 250))
+	expectPanic(t, "iterate", func() { graph.iterate(state) })
+}
@@ -0,0 +1,58 @@
+package kvstore
+
+import "fmt"
+
+// key prefixes, every entry in the store starts with one of these
+var (
+	ParamsKey             = []byte{0x01} // α and ε
+	NodeKeyPrefix         = []byte{0x02} // <id> -> cached pRank and nRank
+	EdgeKeyPrefix         = []byte{0x03} // <source><target> -> signed weight
+	PersonalizationPrefix = []byte{0x04} // <id> -> personalization node
+	CommitmentKey         = []byte{0x05} // commitment of the last results
+)
+
+// NodeKey returns the key of a node
+func NodeKey(id string) []byte {
+	return concat(NodeKeyPrefix, lengthPrefix([]byte(id)))
+}
+
+// EdgeKey returns the key of the edge from source to target
+func EdgeKey(source, target string) []byte {
+	return concat(EdgeKeyPrefix, lengthPrefix([]byte(source)), lengthPrefix([]byte(target)))
+}
+
+// EdgesFromKey returns the prefix of all the edges going out of source
+func EdgesFromKey(source string) []byte {
+	return concat(EdgeKeyPrefix, lengthPrefix([]byte(source)))
+}
+
+// PersonalizationKey returns the key of a personalization node
+func PersonalizationKey(id string) []byte {
+	return concat(PersonalizationPrefix, lengthPrefix([]byte(id)))
+}
+
+// lengthPrefix prefixes bz with its length so ids can't run into each other
+// ids are limited to 255 bytes
+func lengthPrefix(bz []byte) []byte {
+	if len(bz) > 255 {
+		panic(fmt.Sprintf("kvstore: id is too long: %d bytes, max 255", len(bz)))
+	}
+	return append([]byte{byte(len(bz))}, bz...)
+}
+
+// splitLengthPrefixed reads a length prefixed value and returns it with the rest of bz
+func splitLengthPrefixed(bz []byte) ([]byte, []byte, error) {
+	if len(bz) == 0 || len(bz) < int(bz[0])+1 {
+		return nil, nil, fmt.Errorf("kvstore: invalid length prefixed value %X", bz)
+	}
+	n := int(bz[0]) + 1
+	return bz[1:n], bz[n:], nil
+}
+
+func concat(parts ...[]byte) []byte {
+	var key []byte
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
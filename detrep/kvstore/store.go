@@ -0,0 +1,307 @@
+// Package kvstore persists the inputs of a detrep graph in a cosmos-sdk KVStore
+// nodes with their cached ranks, signed edges, personalization nodes and params
+// are stored under deterministic keys, see keys.go
+// the graph is rebuilt from the store for ranking and the results are written back as cached ranks
+package kvstore
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/detrep"
+)
+
+// Params are the rank parameters
+type Params struct {
+	Alpha    detrep.Uint // α, with detrep precision
+	Epsilon  detrep.Uint // ε, with detrep precision
+	Decimals int         // detrep precision, detrep.Decimals if 0
+}
+
+// Store reads and writes graph data
+type Store struct {
+	store sdk.KVStore
+}
+
+// NewStore returns a Store backed by a KVStore, usually a prefix store of a module
+func NewStore(store sdk.KVStore) Store {
+	return Store{store: store}
+}
+
+// SetParams stores the rank parameters
+func (s Store) SetParams(params Params) {
+	bz := concat(marshalUint(params.Alpha), marshalUint(params.Epsilon))
+	if params.Decimals != 0 {
+		bz = concat(bz, marshalUint(detrep.NewUint(uint64(params.Decimals))))
+	}
+	s.store.Set(ParamsKey, bz)
+}
+
+// GetParams returns the rank parameters, false if they were never set
+func (s Store) GetParams() (Params, bool) {
+	bz := s.store.Get(ParamsKey)
+	if bz == nil {
+		return Params{}, false
+	}
+	// decimals are only stored if they are not the default
+	values := mustSplitUints(bz, -1)
+	if len(values) < 2 {
+		panic(fmt.Errorf("kvstore: invalid params %X", bz))
+	}
+	params := Params{Alpha: values[0], Epsilon: values[1]}
+	if len(values) > 2 {
+		params.Decimals = int(values[2].Uint64())
+	}
+	return params, true
+}
+
+// SetNode stores the cached ranks of a node
+// its signature matches the Rank callback so results can be written back directly
+func (s Store) SetNode(id string, pRank detrep.Uint, nRank detrep.Uint) {
+	s.store.Set(NodeKey(id), concat(marshalUint(pRank), marshalUint(nRank)))
+}
+
+// GetNode returns a node with its cached ranks, false if it doesn't exist
+func (s Store) GetNode(id string) (detrep.Node, bool) {
+	bz := s.store.Get(NodeKey(id))
+	if bz == nil {
+		return detrep.Node{}, false
+	}
+	values := mustSplitUints(bz, 2)
+	return detrep.NewNode(id, values[0], values[1]), true
+}
+
+// node returns the stored node or a node with 0 ranks
+func (s Store) node(id string) detrep.Node {
+	if node, ok := s.GetNode(id); ok {
+		return node
+	}
+	return detrep.NewNode(id, detrep.ZeroUint(), detrep.ZeroUint())
+}
+
+// IterateNodes calls cb for every node in key order until it returns true
+func (s Store) IterateNodes(cb func(node detrep.Node) (stop bool)) {
+	iterator := sdk.KVStorePrefixIterator(s.store, NodeKeyPrefix)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		id := mustSplitID(iterator.Key()[len(NodeKeyPrefix):])
+		values := mustSplitUints(iterator.Value(), 2)
+		if cb(detrep.NewNode(id, values[0], values[1])) {
+			break
+		}
+	}
+}
+
+// SetEdge stores the signed weight of the link from source to target
+// negative weights are downvotes, a weight of 0 deletes the edge
+// nodes are created with 0 ranks if they don't exist
+func (s Store) SetEdge(source, target string, weight detrep.Int) {
+	if weight.IsZero() {
+		s.DeleteEdge(source, target)
+		return
+	}
+	for _, id := range []string{source, target} {
+		if !s.store.Has(NodeKey(id)) {
+			s.SetNode(id, detrep.ZeroUint(), detrep.ZeroUint())
+		}
+	}
+	s.store.Set(EdgeKey(source, target), marshalInt(weight))
+}
+
+// GetEdge returns the signed weight of the link from source to target, false if it doesn't exist
+func (s Store) GetEdge(source, target string) (detrep.Int, bool) {
+	bz := s.store.Get(EdgeKey(source, target))
+	if bz == nil {
+		return detrep.ZeroInt(), false
+	}
+	return mustUnmarshalInt(bz), true
+}
+
+// DeleteEdge removes the link from source to target
+func (s Store) DeleteEdge(source, target string) {
+	s.store.Delete(EdgeKey(source, target))
+}
+
+// IterateEdges calls cb for every edge in key order (by source, then target) until it returns true
+func (s Store) IterateEdges(cb func(source, target string, weight detrep.Int) (stop bool)) {
+	s.iterateEdges(EdgeKeyPrefix, cb)
+}
+
+// IterateEdgesFrom calls cb for every edge going out of source until it returns true
+func (s Store) IterateEdgesFrom(source string, cb func(source, target string, weight detrep.Int) (stop bool)) {
+	s.iterateEdges(EdgesFromKey(source), cb)
+}
+
+func (s Store) iterateEdges(prefix []byte, cb func(source, target string, weight detrep.Int) (stop bool)) {
+	iterator := sdk.KVStorePrefixIterator(s.store, prefix)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		source, rest, err := splitLengthPrefixed(iterator.Key()[len(EdgeKeyPrefix):])
+		if err != nil {
+			panic(err)
+		}
+		target := mustSplitID(rest)
+		if cb(string(source), target, mustUnmarshalInt(iterator.Value())) {
+			break
+		}
+	}
+}
+
+// AddPersonalizationNode adds a node to the personalization vector
+func (s Store) AddPersonalizationNode(id string) {
+	if !s.store.Has(NodeKey(id)) {
+		s.SetNode(id, detrep.ZeroUint(), detrep.ZeroUint())
+	}
+	s.store.Set(PersonalizationKey(id), []byte{1})
+}
+
+// RemovePersonalizationNode removes a node from the personalization vector
+func (s Store) RemovePersonalizationNode(id string) {
+	s.store.Delete(PersonalizationKey(id))
+}
+
+// Personalization returns the personalization vector sorted by key
+func (s Store) Personalization() []string {
+	iterator := sdk.KVStorePrefixIterator(s.store, PersonalizationPrefix)
+	defer iterator.Close()
+
+	ids := []string{}
+	for ; iterator.Valid(); iterator.Next() {
+		ids = append(ids, mustSplitID(iterator.Key()[len(PersonalizationPrefix):]))
+	}
+	return ids
+}
+
+// Load builds a graph from the store
+// personalization nodes are added first, then all the edges in key order
+// nodes start with their cached ranks and the negConsumer with the cached rank of NegConsumerID
+func (s Store) Load() (*detrep.Graph, error) {
+	params, ok := s.GetParams()
+	if !ok {
+		return nil, fmt.Errorf("kvstore: params are not set")
+	}
+
+	options := []detrep.Option{}
+	if params.Decimals != 0 {
+		options = append(options, detrep.WithDecimals(params.Decimals))
+	}
+	graph := detrep.NewGraph(params.Alpha, params.Epsilon, s.node(detrep.NegConsumerID).PRank, options...)
+
+	for _, id := range s.Personalization() {
+		graph.AddPersonalizationNode(s.node(id))
+	}
+
+	// cache the nodes we read, edges reference them many times
+	nodes := map[string]detrep.Node{}
+	getNode := func(id string) detrep.Node {
+		if node, ok := nodes[id]; ok {
+			return node
+		}
+		nodes[id] = s.node(id)
+		return nodes[id]
+	}
+
+	s.IterateEdges(func(source, target string, weight detrep.Int) bool {
+		graph.Link(getNode(source), getNode(target), weight)
+		return false
+	})
+	return graph, nil
+}
+
+// Rank loads the graph, ranks it and writes the results back as cached ranks
+// results are written in id order with their commitment and also returned keyed by id
+func (s Store) Rank() (map[string]detrep.Ranks, error) {
+	graph, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return s.RankGraph(graph)
+}
+
+// RankGraph is Rank for a graph returned by Load, so a Meter or MaxIterations can be set first
+// nothing is written if ranking fails, the error is the one returned by TryRank
+func (s Store) RankGraph(graph *detrep.Graph) (map[string]detrep.Ranks, error) {
+	results := map[string]detrep.Ranks{}
+	err := graph.TryRank(func(id string, pRank detrep.Uint, nRank detrep.Uint) {
+		results[id] = detrep.Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.WriteResults(results)
+	s.store.Set(CommitmentKey, detrep.Commitment(results))
+	return results, nil
+}
+
+// Commitment returns the commitment of the results of the last Rank, nil if the graph was never ranked
+func (s Store) Commitment() []byte {
+	return s.store.Get(CommitmentKey)
+}
+
+// WriteResults stores the ranks of every result in id order
+func (s Store) WriteResults(results map[string]detrep.Ranks) {
+	ids := make([]string, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		s.SetNode(id, results[id].PRank, results[id].NRank)
+	}
+}
+
+func marshalUint(u detrep.Uint) []byte {
+	bz, err := u.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return lengthPrefix(bz)
+}
+
+func marshalInt(i detrep.Int) []byte {
+	bz, err := i.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+func mustUnmarshalInt(bz []byte) detrep.Int {
+	var i detrep.Int
+	if err := i.Unmarshal(bz); err != nil {
+		panic(err)
+	}
+	return i
+}
+
+// mustSplitUints reads n length prefixed Uints, or all of them if n is negative
+func mustSplitUints(bz []byte, n int) []detrep.Uint {
+	values := []detrep.Uint{}
+	for i := 0; i != n && (n >= 0 || len(bz) > 0); i++ {
+		value, rest, err := splitLengthPrefixed(bz)
+		if err != nil {
+			panic(err)
+		}
+		var u detrep.Uint
+		if err := u.Unmarshal(value); err != nil {
+			panic(err)
+		}
+		values = append(values, u)
+		bz = rest
+	}
+	return values
+}
+
+// mustSplitID reads a length prefixed id that should take the rest of bz
+func mustSplitID(bz []byte) string {
+	id, rest, err := splitLengthPrefixed(bz)
+	if err != nil || len(rest) != 0 {
+		panic(fmt.Errorf("kvstore: invalid key %X", bz))
+	}
+	return string(id)
+}
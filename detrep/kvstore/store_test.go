@@ -0,0 +1,189 @@
+package kvstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store/dbadapter"
+	"github.com/cosmos/cosmos-sdk/store/iavl"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/detrep"
+	dbm "github.com/tendermint/tm-db"
+)
+
+type testLink struct {
+	source, target string
+	weight         float64
+}
+
+var testLinks = []testLink{
+	{"a", "b", 1.0},
+	{"a", "c", 2.0},
+	{"b", "c", 1.0},
+	{"c", "d", 1.0},
+	{"b", "d", -1.0},
+	{"d", "e", 1.0},
+	{"c", "e", -1.0},
+}
+
+func weight(w float64) detrep.Int {
+	return detrep.NewInt(int64(w * 1e9)).Mul(detrep.NewInt(1e9))
+}
+
+func newStores(t *testing.T) map[string]sdk.KVStore {
+	tree, err := iavl.LoadStore(dbm.NewMemDB(), storetypes.CommitID{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]sdk.KVStore{
+		"memdb": dbadapter.Store{DB: dbm.NewMemDB()},
+		"iavl":  tree,
+	}
+}
+
+func setupStore(kv sdk.KVStore) Store {
+	s := NewStore(kv)
+	s.SetParams(Params{Alpha: detrep.FtoBD(0.85), Epsilon: detrep.FtoBD(0.000001)})
+	s.AddPersonalizationNode("a")
+	for _, link := range testLinks {
+		s.SetEdge(link.source, link.target, weight(link.weight))
+	}
+	return s
+}
+
+// rankDirect builds the same graph in memory
+func rankDirect(results map[string]detrep.Ranks) map[string]detrep.Ranks {
+	node := func(id string) detrep.Node {
+		if r, ok := results[id]; ok {
+			return detrep.NewNode(id, r.PRank, r.NRank)
+		}
+		return detrep.NewNode(id, detrep.ZeroUint(), detrep.ZeroUint())
+	}
+
+	negConsumerRank := detrep.ZeroUint()
+	if r, ok := results[detrep.NegConsumerID]; ok {
+		negConsumerRank = r.PRank
+	}
+	graph := detrep.NewGraphHelper(0.85, 0.000001, negConsumerRank)
+	graph.AddPersonalizationNode(node("a"))
+	for _, link := range testLinks {
+		graph.Link(node(link.source), node(link.target), weight(link.weight))
+	}
+
+	next := map[string]detrep.Ranks{}
+	graph.Rank(func(id string, pRank detrep.Uint, nRank detrep.Uint) {
+		next[id] = detrep.Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	return next
+}
+
+func TestRank(t *testing.T) {
+	for name, kv := range newStores(t) {
+		s := setupStore(kv)
+
+		expected := map[string]detrep.Ranks{}
+		// the second pass uses the cached ranks written by the first one
+		for pass := 0; pass < 2; pass++ {
+			expected = rankDirect(expected)
+			actual, err := s.Rank()
+			if err != nil {
+				t.Fatal(name, err)
+			}
+			if len(actual) != len(expected) {
+				t.Fatalf("%s pass %d: expected %d results, got %d", name, pass, len(expected), len(actual))
+			}
+			for id, e := range expected {
+				a := actual[id]
+				if !a.PRank.Equal(e.PRank) || !a.NRank.Equal(e.NRank) {
+					t.Errorf("%s pass %d: %s expected %v got %v", name, pass, id, e, a)
+				}
+				stored, ok := s.GetNode(id)
+				if !ok || !stored.PRank.Equal(e.PRank) || !stored.NRank.Equal(e.NRank) {
+					t.Errorf("%s pass %d: %s results were not written back", name, pass, id)
+				}
+			}
+			if !bytes.Equal(s.Commitment(), detrep.Commitment(expected)) {
+				t.Errorf("%s pass %d: commitment was not written", name, pass)
+			}
+		}
+	}
+}
+
+func TestEdges(t *testing.T) {
+	s := setupStore(dbadapter.Store{DB: dbm.NewMemDB()})
+
+	w, ok := s.GetEdge("b", "d")
+	if !ok || !w.Equal(weight(-1)) {
+		t.Errorf("expected a negative edge from b to d, got %v", w)
+	}
+
+	var edges []testLink
+	s.IterateEdgesFrom("a", func(source, target string, w detrep.Int) bool {
+		edges = append(edges, testLink{source, target, float64(w.Quo(detrep.NewInt(1e18)).Int64())})
+		return false
+	})
+	if len(edges) != 2 || edges[0] != (testLink{"a", "b", 1}) || edges[1] != (testLink{"a", "c", 2}) {
+		t.Errorf("unexpected edges from a %v", edges)
+	}
+
+	s.SetEdge("a", "b", detrep.ZeroInt())
+	if _, ok := s.GetEdge("a", "b"); ok {
+		t.Errorf("a weight of 0 should delete the edge")
+	}
+
+	s.RemovePersonalizationNode("a")
+	if len(s.Personalization()) != 0 {
+		t.Errorf("expected an empty personalization vector")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	// ids are length prefixed so "ab" -> "c" and "a" -> "bc" don't collide
+	if bytes.Equal(EdgeKey("ab", "c"), EdgeKey("a", "bc")) {
+		t.Errorf("edge keys should not collide")
+	}
+	if !bytes.HasPrefix(EdgeKey("a", "b"), EdgesFromKey("a")) || bytes.HasPrefix(EdgeKey("ab", "c"), EdgesFromKey("a")) {
+		t.Errorf("edges should be grouped by source")
+	}
+	expected := []byte{0x02, 0x01, 'a'}
+	if !bytes.Equal(NodeKey("a"), expected) {
+		t.Errorf("expected node key %X got %X", expected, NodeKey("a"))
+	}
+}
+
+func TestLoadWithoutParams(t *testing.T) {
+	s := NewStore(dbadapter.Store{DB: dbm.NewMemDB()})
+	if _, err := s.Load(); err == nil {
+		t.Errorf("expected an error when params are not set")
+	}
+}
+
+func TestParamsDecimals(t *testing.T) {
+	s := NewStore(dbadapter.Store{DB: dbm.NewMemDB()})
+
+	s.SetParams(Params{Alpha: detrep.FtoBD(0.85), Epsilon: detrep.FtoBD(0.000001)})
+	if params, _ := s.GetParams(); params.Decimals != 0 {
+		t.Errorf("expected default decimals, got %d", params.Decimals)
+	}
+	graph, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graph.Decimals != detrep.Decimals {
+		t.Errorf("expected %d decimals, got %d", detrep.Decimals, graph.Decimals)
+	}
+
+	s.SetParams(Params{Alpha: detrep.FtoBDWithDecimals(0.85, 9), Epsilon: detrep.NewUint(100), Decimals: 9})
+	params, _ := s.GetParams()
+	if params.Decimals != 9 || !params.Alpha.Equal(detrep.NewUint(850000000)) || !params.Epsilon.Equal(detrep.NewUint(100)) {
+		t.Errorf("unexpected params %v", params)
+	}
+	graph, err = s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !graph.Precision.Equal(detrep.NewUint(1000000000)) {
+		t.Errorf("expected a precision of 1e9, got %s", graph.Precision)
+	}
+}
@@ -0,0 +1,150 @@
+package detrep
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// leaves and inner nodes are hashed with different prefixes so a leaf can't be passed off as an inner node
+var (
+	leafPrefix  = []byte{0}
+	innerPrefix = []byte{1}
+)
+
+// ErrInvalidProof is returned when a proof doesn't lead to the expected root
+var ErrInvalidProof = errors.New("invalid merkle proof")
+
+// MerkleTree is a merkle tree over results sorted by id
+// the tree has the same shape as RFC 6962 (and tendermint) trees:
+// the left subtree holds the largest power of 2 of leaves that is smaller than the number of leaves
+type MerkleTree struct {
+	results []Ranks
+	leaves  [][]byte
+	index   map[string]int
+}
+
+// Proof proves that a result is part of the results committed to by a root
+type Proof struct {
+	Index int      `json:"index"` // position of the result in id order
+	Total int      `json:"total"` // number of results
+	Aunts [][]byte `json:"aunts"` // hashes of the sibling subtrees, from the leaf up
+}
+
+// NewMerkleTree builds the tree of a result set
+func NewMerkleTree(results map[string]Ranks) *MerkleTree {
+	sorted := SortedResults(results)
+	tree := &MerkleTree{
+		results: sorted,
+		leaves:  make([][]byte, len(sorted)),
+		index:   make(map[string]int, len(sorted)),
+	}
+	for i, result := range sorted {
+		tree.leaves[i] = leafHash(result)
+		tree.index[result.ID] = i
+	}
+	return tree
+}
+
+// Root returns the root hash, the hash of an empty tree is the hash of no data
+func (tree *MerkleTree) Root() []byte {
+	return rootHash(tree.leaves)
+}
+
+// Prove returns the result of a node and the proof that it is part of the tree
+func (tree *MerkleTree) Prove(id string) (Ranks, Proof, error) {
+	i, ok := tree.index[id]
+	if !ok {
+		return Ranks{}, Proof{}, fmt.Errorf("node %s not found", id)
+	}
+	proof := Proof{Index: i, Total: len(tree.leaves), Aunts: aunts(tree.leaves, i)}
+	return tree.results[i], proof, nil
+}
+
+// VerifyProof checks that result is part of the results committed to by root
+func VerifyProof(root []byte, result Ranks, proof Proof) error {
+	if proof.Total <= 0 || proof.Index < 0 || proof.Index >= proof.Total {
+		return fmt.Errorf("%w: index %d out of range %d", ErrInvalidProof, proof.Index, proof.Total)
+	}
+	computed, err := computeRoot(proof.Index, proof.Total, leafHash(result), proof.Aunts)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(computed, root) {
+		return fmt.Errorf("%w: root mismatch for %s", ErrInvalidProof, result.ID)
+	}
+	return nil
+}
+
+func leafHash(result Ranks) []byte {
+	hash := sha256.New()
+	hash.Write(leafPrefix)
+	hash.Write(EncodeResult(result))
+	return hash.Sum(nil)
+}
+
+func innerHash(left, right []byte) []byte {
+	hash := sha256.New()
+	hash.Write(innerPrefix)
+	hash.Write(left)
+	hash.Write(right)
+	return hash.Sum(nil)
+}
+
+// splitPoint returns the largest power of 2 smaller than n, n must be > 1
+func splitPoint(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
+
+func rootHash(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		hash := sha256.Sum256(nil)
+		return hash[:]
+	case 1:
+		return leaves[0]
+	}
+	k := splitPoint(len(leaves))
+	return innerHash(rootHash(leaves[:k]), rootHash(leaves[k:]))
+}
+
+// aunts returns the sibling hashes on the path from leaf i to the root, from the bottom up
+func aunts(leaves [][]byte, i int) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if i < k {
+		return append(aunts(leaves[:k], i), rootHash(leaves[k:]))
+	}
+	return append(aunts(leaves[k:], i-k), rootHash(leaves[:k]))
+}
+
+// computeRoot hashes a leaf with its aunts
+func computeRoot(i, total int, leaf []byte, aunts [][]byte) ([]byte, error) {
+	if total == 1 {
+		if len(aunts) != 0 {
+			return nil, fmt.Errorf("%w: too many aunts", ErrInvalidProof)
+		}
+		return leaf, nil
+	}
+	if len(aunts) == 0 {
+		return nil, fmt.Errorf("%w: missing aunts", ErrInvalidProof)
+	}
+	last := len(aunts) - 1
+	k := splitPoint(total)
+	if i < k {
+		left, err := computeRoot(i, k, leaf, aunts[:last])
+		if err != nil {
+			return nil, err
+		}
+		return innerHash(left, aunts[last]), nil
+	}
+	right, err := computeRoot(i-k, total-k, leaf, aunts[:last])
+	if err != nil {
+		return nil, err
+	}
+	return innerHash(aunts[last], right), nil
+}
@@ -0,0 +1,101 @@
+package detrep
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func testResults(n int) map[string]Ranks {
+	results := map[string]Ranks{}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("node%02d", i)
+		results[id] = Ranks{ID: id, PRank: NewUint(uint64(i * 1000)), NRank: NewUint(uint64(i))}
+	}
+	return results
+}
+
+func TestMerkleRoot(t *testing.T) {
+	results := testResults(3)
+	sorted := SortedResults(results)
+	l0, l1, l2 := leafHash(sorted[0]), leafHash(sorted[1]), leafHash(sorted[2])
+
+	expected := innerHash(innerHash(l0, l1), l2)
+	if !bytes.Equal(Commitment(results), expected) {
+		t.Error("commitment should be the merkle root of the sorted results")
+	}
+	if !bytes.Equal(Commitment(testResults(1)), leafHash(SortedResults(testResults(1))[0])) {
+		t.Error("the root of a single result should be its leaf")
+	}
+	if len(Commitment(map[string]Ranks{})) != 32 {
+		t.Error("empty results should have a root")
+	}
+}
+
+func TestMerkleProofs(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 7, 8, 13} {
+		results := testResults(n)
+		tree := NewMerkleTree(results)
+		root := tree.Root()
+
+		for id, expected := range results {
+			result, proof, err := tree.Prove(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.PRank.Equal(expected.PRank) || !result.NRank.Equal(expected.NRank) {
+				t.Errorf("n=%d: wrong result for %s: %v", n, id, result)
+			}
+			if err := VerifyProof(root, result, proof); err != nil {
+				t.Errorf("n=%d: proof of %s should verify: %v", n, id, err)
+			}
+
+			forged := result
+			forged.PRank = result.PRank.Add(OneUint())
+			if err := VerifyProof(root, forged, proof); !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("n=%d: proof of a forged rank for %s should fail", n, id)
+			}
+			renamed := result
+			renamed.ID = "other"
+			if err := VerifyProof(root, renamed, proof); !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("n=%d: proof of another id should fail", n)
+			}
+			if n > 1 {
+				moved := proof
+				moved.Index = (proof.Index + 1) % n
+				if err := VerifyProof(root, result, moved); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("n=%d: proof with the wrong index should fail", n)
+				}
+				truncated := proof
+				truncated.Aunts = proof.Aunts[1:]
+				if err := VerifyProof(root, result, truncated); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("n=%d: proof with missing aunts should fail", n)
+				}
+			}
+		}
+	}
+}
+
+func TestMerkleRankedGraph(t *testing.T) {
+	_, results := rankTwice(func(graph *Graph, n map[string]Node) {
+		graph.LinkHelper(n["a"], n["b"], 2.0)
+		graph.LinkHelper(n["a"], n["c"], 1.0)
+		graph.LinkHelper(n["b"], n["c"], -1.0)
+	})
+	tree := NewMerkleTree(results)
+
+	result, proof, err := tree.Prove("c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyProof(Commitment(results), result, proof); err != nil {
+		t.Error(err)
+	}
+	if _, _, err := tree.Prove("missing"); err == nil {
+		t.Error("expected an error for a missing node")
+	}
+	if err := VerifyProof(Commitment(results), result, Proof{Index: 5, Total: 5}); !errors.Is(err, ErrInvalidProof) {
+		t.Error("expected an error for an out of range index")
+	}
+}
@@ -0,0 +1,22 @@
+package detrep
+
+// IterationStats describes the state of a Rank computation after an iteration
+// all values use the graph precision
+type IterationStats struct {
+	Iteration       int  // number of completed iterations
+	Delta           Uint // global error (Δ) between the last two iterations
+	DanglingWeight  Uint // α * rank of the nodes with no outgoing links, redistributed in the last iteration
+	NegConsumerRank Uint // rank of the negConsumer node
+	Dust            Uint // rank lost to rounding in the last iteration, redistributed so ranks add up to Precision
+}
+
+// Observer can be attached to a graph to follow the progress of Rank
+// the graph passed to the observer is the one being ranked and should not be modified
+type Observer interface {
+	// Start is called once the graph is finalized and the start scores are initialized
+	Start(graph *Graph)
+	// Iteration is called after every power iteration
+	Iteration(graph *Graph, stats IterationStats)
+	// Complete is called after convergence, before negative nodes are merged into the results
+	Complete(graph *Graph, stats IterationStats)
+}
@@ -1,7 +1,8 @@
 package detrep
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"fmt"
+	"sort"
 )
 
 // Rank computes the PageRank of every node in the directed graph.
@@ -9,133 +10,238 @@ import (
 // ε (epsilon) is the convergence criteria, usually set to a tiny value.
 //
 // This method will run as many iterations as needed, until the graph converges.
-func (graph Graph) Rank(callback func(key string, pRank sdk.Uint, nRank sdk.Uint)) {
-	graph.Finalize()
-
-	one := graph.Precision
+// it panics with a NotConvergedError if the graph doesn't converge within graph.MaxIterations,
+// use TryRank to get an error instead
+func (graph *Graph) Rank(callback func(key string, pRank Uint, nRank Uint)) {
+	state, iterationGas := graph.prepare()
 
-	Δ := one
-	N := sdk.NewUint(uint64(len(graph.Nodes)))
-	pVector := graph.Params.Personalization
+	Δ := graph.Precision
 	ε := graph.Params.ε
-	α := graph.Params.α
 
-	personalized := len(pVector) > 0
+	graph.initScores(state)
+	state.acc.load(graph, state.keys)
+
+	if graph.Observer != nil {
+		graph.Observer.Start(graph)
+	}
+
+	stats := IterationStats{Delta: Δ, DanglingWeight: ZeroUint(), NegConsumerRank: ZeroUint(), Dust: ZeroUint()}
+	iter := 0
+	for Δ.GT(ε) {
+		if iter >= graph.MaxIterations {
+			panic(NotConvergedError{Iterations: iter, Delta: Δ})
+		}
+		// charge before doing the work, the amount doesn't depend on map order
+		graph.consumeGas(iterationGas, GasDescIteration)
+
+		var danglingWeight, dust Uint
+		Δ, danglingWeight, dust = graph.iterate(state)
+		state.roundingLoss = state.roundingLoss.Add(dust)
+		iter++
+
+		stats = IterationStats{
+			Iteration:       iter,
+			Delta:           Δ,
+			DanglingWeight:  danglingWeight,
+			NegConsumerRank: state.acc.rank(graph.NegConsumer.ID),
+			Dust:            dust,
+		}
+		if graph.Observer != nil {
+			// observers can look at the ranks of the nodes
+			state.acc.store(graph, state.keys)
+			graph.Observer.Iteration(graph, stats)
+		}
+	}
+	state.acc.store(graph, state.keys)
+
+	if graph.Observer != nil {
+		graph.Observer.Complete(graph, stats)
+	}
+
+	// keep what we need to explain the results
+	state.stats = stats
+	graph.rankState = state
+
+	// fmt.Println("iterations:", iter, "Δ", Δ)
+	graph.processResults(callback)
+}
+
+// NotConvergedError is the panic value of Rank when Δ is still above ε after MaxIterations
+// this happens when ε is too small for the rounding error of the iterations, see WithDecimals
+type NotConvergedError struct {
+	Iterations int
+	Delta      Uint // Δ of the last iteration
+}
+
+func (e NotConvergedError) Error() string {
+	return fmt.Sprintf("rank did not converge after %d iterations, Δ is %s", e.Iterations, e.Delta)
+}
+
+// MinEpsilon returns the smallest ε Rank is expected to converge with for the current graph,
+// 10 units per node and edge (including the negConsumer and its links)
+// every iteration rounds each node and edge down by less than a unit, so with a smaller ε
+// the ranks can keep moving between states that are a few rounding errors apart
+func (graph *Graph) MinEpsilon() Uint {
+	nodes := uint64(len(graph.Nodes)) + 1
+	edges := add(graph.edgeCount(), uint64(len(graph.NegNodes)))
+	return NewUint(mul(add(nodes, edges), 10))
+}
+
+// prepare finalizes the graph and normalizes the edge weights
+// it returns the state shared by all iterations and the gas charged by each iteration
+func (graph *Graph) prepare() (*rankState, uint64) {
+	graph.Finalize()
 
 	// these are personlaization node weights
 	// we adjust them so that all p nodes have the same outgoing link weight
 	pWeights := graph.initPersonalizationNodes()
 
+	// edges don't change from here on so every iteration costs the same
+	edges := graph.edgeCount()
+	iterationGas := graph.Gas.iterationCost(uint64(len(graph.Nodes)), edges)
+	graph.consumeGas(mul(edges, graph.Gas.Relaxation), GasDescNormalize)
+
 	// Normalize all the edge weights so that their sum amounts to 1.
 	for source := range graph.Nodes {
-		if graph.Nodes[source].degree.GT(sdk.ZeroUint()) {
+		if graph.Nodes[source].degree.GT(ZeroUint()) {
 			for target := range graph.Edges[source] {
 				graph.Edges[source][target] = graph.Edges[source][target].Mul(graph.Precision).Quo(graph.Nodes[source].degree)
 			}
 		}
 	}
 
-	graph.initScores(N, pWeights)
+	keys := make([]string, 0, len(graph.Nodes))
+	for key := range graph.Nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	iter := 0
-	for Δ.GT(ε) {
-		danglingWeight := sdk.ZeroUint()
-		nodes := map[string]sdk.Uint{}
+	state := &rankState{
+		n:            NewUint(uint64(len(graph.Nodes))),
+		pWeights:     pWeights,
+		keys:         keys,
+		roundingLoss: ZeroUint(),
+		acc:          newAccumulators(graph, keys),
+	}
+	return state, iterationGas
+}
 
-		for key, value := range graph.Nodes {
-			nodes[key] = value.PRank
+// iterate runs a single power iteration on the accumulators of state
+// it returns the global error (Δ) with the previous iteration, the redistributed dangling weight
+// and the rank lost to rounding, which is redistributed so the ranks add up to Precision
+func (graph *Graph) iterate(state *rankState) (Uint, Uint, Uint) {
+	acc := state.acc
+	one := graph.Precision
+	α := graph.Params.α
+	personalized := len(graph.Params.Personalization) > 0
 
-			if value.degree.IsZero() {
-				danglingWeight = danglingWeight.Add(value.PRank)
-			}
+	acc.ranks, acc.prev = acc.prev, acc.ranks
+	ranks, prev := acc.ranks, acc.prev
 
-			graph.Nodes[key].PRank = sdk.ZeroUint()
+	acc.sum.SetInt64(0)
+	for i := range prev {
+		if acc.dangling[i] {
+			addTo(&acc.sum, &prev[i])
 		}
+		ranks[i].SetInt64(0)
+	}
+	danglingWeight := NewUintFromBigInt(&acc.sum).Mul(α).Quo(one)
 
-		danglingWeight = danglingWeight.Mul(α).Quo(graph.Precision)
-
-		for source := range graph.Nodes {
-			for target, weight := range graph.Edges[source] {
-				addWeight := α.Mul(nodes[source]).Quo(graph.Precision).Mul(weight).Quo(graph.Precision)
-				graph.Nodes[target].PRank = graph.Nodes[target].PRank.Add(addWeight)
-			}
-
-			if !personalized {
-				graph.Nodes[source].PRank = graph.Nodes[source].PRank.Add(one.Sub(α).Quo(N).Add(danglingWeight.Quo(N)))
-			}
+	for source, edges := range acc.edges {
+		if len(edges) == 0 {
+			continue
 		}
-
-		// random jump + dangling weights are transferred to admins
-		// this makes pagerank sybil resistant
-		if personalized {
-			for i, root := range pVector {
-				graph.Nodes[root].PRank = graph.Nodes[root].PRank.Add((one.Sub(α).Add(danglingWeight)).Mul(pWeights[i])).Quo(graph.Precision)
-			}
+		// α * rank / Precision is the same for all outgoing links, see contribution
+		mulQuo(&acc.share, &acc.rem, α.i, &prev[source], one.i)
+		for _, edge := range edges {
+			addTo(&ranks[edge.target], mulQuo(&acc.tmp, &acc.rem, &acc.share, edge.weight, one.i))
 		}
+	}
 
-		Δ = sdk.ZeroUint()
-
-		for key, value := range graph.Nodes {
-			var diff sdk.Uint
-			// if _, ok := nodes[key]; ok == false {
-			// 	nodes[key] = sdk.ZeroUint()
-			// }
-			if value.PRank.LT(nodes[key]) {
-				diff = nodes[key].Sub(value.PRank)
-			} else {
-				diff = value.PRank.Sub(nodes[key])
-			}
-			Δ = Δ.Add(diff)
+	// random jump + dangling weights are transferred to admins
+	// this makes pagerank sybil resistant
+	if personalized {
+		teleport := one.Sub(α).Add(danglingWeight)
+		for i, root := range acc.pIndex {
+			addTo(&ranks[root], mulQuo(&acc.tmp, &acc.rem, teleport.i, state.pWeights[i].i, one.i))
+		}
+	} else if len(ranks) > 0 {
+		teleport := one.Sub(α).Quo(state.n).Add(danglingWeight.Quo(state.n))
+		for i := range ranks {
+			addTo(&ranks[i], teleport.i)
 		}
-		iter++
 	}
 
-	// fmt.Println("iterations:", iter, "Δ", Δ)
-	graph.processResults(callback)
+	acc.sum.SetInt64(0)
+	for i := range ranks {
+		addTo(&acc.sum, &ranks[i])
+	}
+	dust := one.Sub(MinUint(one, NewUintFromBigInt(&acc.sum)))
+
+	// Δ doesn't count the dust: the rank lost to rounding is at least dust away from prev,
+	// and giving it back can move the ranks between two states that are dust apart forever
+	acc.sum.SetInt64(0)
+	for i := range ranks {
+		acc.tmp.Sub(&ranks[i], &prev[i])
+		addTo(&acc.sum, acc.tmp.Abs(&acc.tmp))
+	}
+	Δ := NewUintFromBigInt(&acc.sum)
+	Δ = Δ.Sub(MinUint(Δ, dust))
+
+	acc.addDust(state, dust)
+	return Δ, danglingWeight, dust
 }
 
 // make sure the total start sum of all scores is 1
 // we initialze the start scores to optimize the computation
-func (graph Graph) initScores(N sdk.Uint, pWeights []sdk.Uint) {
+func (graph Graph) initScores(state *rankState) {
 	// get sum of all node scores
 	personalization := graph.Params.Personalization
-	totalScore := sdk.ZeroUint()
-	for _, node := range graph.Nodes {
-		totalScore = totalScore.Add(node.PRank)
+	totalScore := graph.totalRank()
+
+	// if start sum is close to 1 we keep the cached scores
+	if totalScore.LTE(graph.Precision.MulUint64(9).QuoUint64(10)) {
+		// TODO use prev scores for initialization
+		if len(state.pWeights) == 0 {
+			// initialize all nodes if there is no personalizeation vector
+			for key := range graph.Nodes {
+				graph.Nodes[key].PRank = graph.Nodes[key].PRank.Add(graph.Precision.Sub(totalScore).Quo(state.n))
+			}
+		} else {
+			// initialize personalization vector
+			for i, root := range personalization {
+				graph.Nodes[root].PRank = graph.Nodes[root].PRank.Add(graph.Precision.Sub(totalScore).Mul(state.pWeights[i]).Quo(graph.Precision))
+			}
+		}
+		totalScore = graph.totalRank()
 	}
 
-	// if start sum is close to 1 we are done
-	if totalScore.GT(graph.Precision.MulUint64(9).QuoUint64(10)) {
+	// start from exactly Precision, iterations can then only lose rank to rounding
+	if totalScore.IsZero() {
 		return
 	}
-
-	// TODO use prev scores for initialization
-	if len(pWeights) == 0 {
-		// initialize all nodes if there is no personalizeation vector
+	if totalScore.GT(graph.Precision) {
 		for key := range graph.Nodes {
-			graph.Nodes[key].PRank = graph.Nodes[key].PRank.Add(graph.Precision.Sub(totalScore).Quo(N))
+			graph.Nodes[key].PRank = graph.Nodes[key].PRank.Mul(graph.Precision).Quo(totalScore)
 		}
-		return
-	}
-	// initialize personalization vector
-	for i, root := range personalization {
-		graph.Nodes[root].PRank = graph.Nodes[root].PRank.Add(graph.Precision.Sub(totalScore).Mul(graph.Precision).Quo(pWeights[i]))
+		totalScore = graph.totalRank()
 	}
+	graph.addDust(state, graph.Precision.Sub(totalScore))
 }
 
 // compute personalization weights based on degree
 // this ensures source nodes will have the same weight
 // we also update start scores here
-func (graph Graph) initPersonalizationNodes() []sdk.Uint {
+func (graph Graph) initPersonalizationNodes() []Uint {
 	pVector := graph.Params.Personalization
-	pWeights := make([]sdk.Uint, len(pVector))
+	pWeights := make([]Uint, len(pVector))
 
-	pWeightsSum := sdk.ZeroUint()
-	scoreSum := sdk.ZeroUint()
+	pWeightsSum := ZeroUint()
+	scoreSum := ZeroUint()
 	for i, key := range pVector {
-		var d sdk.Uint
+		var d Uint
 		// root node score and weight should not be 0
-		if d = graph.Precision; graph.Nodes[key].degree.GT(sdk.ZeroUint()) {
+		if d = graph.Precision; graph.Nodes[key].degree.GT(ZeroUint()) {
 			d = graph.Nodes[key].degree
 		}
 		pWeights[i] = d
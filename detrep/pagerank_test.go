@@ -1,18 +1,17 @@
 package detrep
 
 import (
+	"math"
 	"reflect"
 	"testing"
-
-	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 type Result struct {
-	pRank sdk.Uint
-	nRank sdk.Uint
+	pRank Uint
+	nRank Uint
 }
 
-var zero = sdk.ZeroUint()
+var zero = ZeroUint()
 
 func TestEmpty(t *testing.T) {
 	graph := NewGraphHelper(0.85, 0.000001, zero)
@@ -20,7 +19,7 @@ func TestEmpty(t *testing.T) {
 	actual := map[string]Result{}
 	expected := map[string]Result{}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -54,7 +53,7 @@ func TestSimple(t *testing.T) {
 		"d": {pRank: FtoBD(0.25), nRank: FtoBD(0)},
 	}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -84,7 +83,7 @@ func TestWeighted(t *testing.T) {
 
 	actual := map[string]Result{}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -117,7 +116,7 @@ func TestPersonalized(t *testing.T) {
 		"d": {pRank: FtoBD(0), nRank: FtoBD(0)},
 	}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -154,7 +153,7 @@ func TestPersonalizedNoLinkHelper(t *testing.T) {
 		"d": {pRank: FtoBD(0), nRank: FtoBD(0)},
 	}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -166,6 +165,38 @@ func TestPersonalizedNoLinkHelper(t *testing.T) {
 	}
 }
 
+func TestPersonalizedIncomingLinks(t *testing.T) {
+	// the rank a personalization node gets from its incoming links is kept, the teleport rank is added to it
+	// before the teleport was added inside the division by Precision, so a lost the rank coming from b
+	graph := NewGraphHelper(0.5, 0.000000001, zero)
+
+	a := NewNodeInputHelper("a", 0, 0)
+	b := NewNodeInputHelper("b", 0, 0)
+	c := NewNodeInputHelper("c", 0, 0)
+	d := NewNodeInputHelper("d", 0, 0)
+
+	graph.AddPersonalizationNode(a)
+	graph.AddPersonalizationNode(c)
+	graph.LinkHelper(a, b, 1.0)
+	graph.LinkHelper(b, a, 1.0)
+	graph.LinkHelper(c, d, 1.0)
+
+	actual := map[string]Result{}
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
+		actual[id] = Result{pRank: pRank, nRank: nRank}
+	})
+
+	// a and c get half of the teleport and of the dangling rank of d
+	// c = 1/4 + 1/4 d, d = 1/2 c => c = 2/7, d = 1/7
+	// a = 1/4 + 1/4 d + 1/2 b, b = 1/2 a => a = 8/21, b = 4/21
+	expected := map[string]float64{"a": 8.0 / 21, "b": 4.0 / 21, "c": 2.0 / 7, "d": 1.0 / 7}
+	for id, e := range expected {
+		if diff := math.Abs(float64(actual[id].pRank.Uint64())/math.Pow(10, Decimals) - e); diff > 1e-8 {
+			t.Errorf("%s expected %f but got %s", id, e, actual[id].pRank)
+		}
+	}
+}
+
 func TestCancelOpposites(t *testing.T) {
 	graph := NewGraphHelper(0.85, 0.000001, zero)
 
@@ -185,26 +216,58 @@ func TestCancelOpposites(t *testing.T) {
 
 	actual := map[string]Result{}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
 		}
 	})
 
-	if !actual["b"].pRank.Add(actual["b"].nRank).Equal(sdk.ZeroUint()) {
+	if !actual["b"].pRank.Add(actual["b"].nRank).Equal(ZeroUint()) {
 		t.Errorf("rank of b should be 0 but its %s", actual["b"].pRank.String())
 	}
 
-	if !actual["c"].nRank.Equal(sdk.ZeroUint()) {
+	if !actual["c"].nRank.Equal(ZeroUint()) {
 		t.Errorf("c rank should be positive")
 	}
 
-	if !actual["d"].pRank.Equal(sdk.ZeroUint()) {
+	if !actual["d"].pRank.Equal(ZeroUint()) {
 		t.Errorf("d rank should be negative")
 	}
 }
 
+func TestCancelOppositesDegree(t *testing.T) {
+	// the cancelled weight is removed from the degree of the source, so a -> b and a -> c get 1/2 each
+	// before cancelOpposites took the source node by pointer the degree stayed at 4 and they got 1/4
+	graph := NewGraphHelper(0.5, 0.000000001, zero)
+
+	a := NewNodeInputHelper("a", 0, 0)
+	b := NewNodeInputHelper("b", 0, 0)
+	c := NewNodeInputHelper("c", 0, 0)
+
+	graph.AddPersonalizationNode(a)
+	graph.LinkHelper(a, b, 2.0)
+	graph.LinkHelper(a, b, -1.0)
+	graph.LinkHelper(a, c, 1.0)
+
+	if degree := graph.Nodes["a"].degree; !degree.Equal(FtoBD(2)) {
+		t.Fatalf("the degree of a should be 2, got %s", degree)
+	}
+
+	actual := map[string]Result{}
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
+		actual[id] = Result{pRank: pRank, nRank: nRank}
+	})
+
+	// b = c = α a / 2, a = (1 - α) + α (b + c) => a = 2/3, b = c = 1/6
+	expected := map[string]float64{"a": 2.0 / 3, "b": 1.0 / 6, "c": 1.0 / 6}
+	for id, e := range expected {
+		if diff := math.Abs(float64(actual[id].pRank.Uint64())/math.Pow(10, Decimals) - e); diff > 1e-8 {
+			t.Errorf("%s expected %f but got %s", id, e, actual[id].pRank)
+		}
+	}
+}
+
 func TestNegativeLink(t *testing.T) {
 	graph := NewGraphHelper(0.85, 0.000001, zero)
 
@@ -224,18 +287,18 @@ func TestNegativeLink(t *testing.T) {
 
 	actual := map[string]Result{}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
 		}
 	})
 
-	if actual["d"].nRank.Sub(actual["d"].pRank).LTE(sdk.ZeroUint()) {
+	if actual["d"].nRank.Sub(actual["d"].pRank).LTE(ZeroUint()) {
 		t.Errorf("rank of d should be neagative")
 	}
 
-	if !actual["e"].pRank.Equal(sdk.ZeroUint()) || actual["e"].nRank.Equal(sdk.ZeroUint()) {
+	if !actual["e"].pRank.Equal(ZeroUint()) || actual["e"].nRank.Equal(ZeroUint()) {
 		t.Errorf("pure neagative node has incorrect results")
 	}
 
@@ -261,14 +324,14 @@ func TestNegativeLink(t *testing.T) {
 	graph.LinkHelper(b, d, -1.0)
 	graph.LinkHelper(d, e, 1.0)
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
 		}
 	})
 
-	if !actual["e"].pRank.Equal(sdk.ZeroUint()) {
+	if !actual["e"].pRank.Equal(ZeroUint()) {
 		t.Errorf("weight of neg node should be 0")
 	}
 }
@@ -292,7 +355,7 @@ func TestNegativeConsumer(t *testing.T) {
 
 	actual := map[string]Result{}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -323,7 +386,7 @@ func TestNegativeConsumer(t *testing.T) {
 	graph.LinkHelper(b, d, -1.0)
 	graph.LinkHelper(d, e, 1.0)
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -354,7 +417,7 @@ func TestMaxNeg(t *testing.T) {
 
 	actual := map[string]Result{}
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
@@ -385,7 +448,7 @@ func TestMaxNeg(t *testing.T) {
 	graph.LinkHelper(b, d, -1.0)
 	graph.LinkHelper(d, e, 1.0)
 
-	graph.Rank(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+	graph.Rank(func(id string, pRank Uint, nRank Uint) {
 		actual[id] = Result{
 			pRank: pRank,
 			nRank: nRank,
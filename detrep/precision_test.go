@@ -0,0 +1,266 @@
+package detrep
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/relevant-community/reputation/exact"
+	"github.com/relevant-community/reputation/rep"
+)
+
+type testLink struct {
+	source, target string
+	weight         float64
+}
+
+var precisionLinks = []testLink{
+	{"a", "b", 2.0},
+	{"a", "c", 1.0},
+	{"b", "c", -1.0},
+	{"b", "a", 1.0},
+	{"c", "d", -1.0},
+	{"c", "e", 3.0},
+	{"d", "e", 3.0},
+	{"e", "b", 1.0},
+	{"e", "d", -2.0},
+}
+
+// rankRep ranks the precision graph twice with rep
+func rankRep(ε float64) map[string]rep.Ranks {
+	results := map[string]rep.Ranks{}
+	for pass := 0; pass < 2; pass++ {
+		graph := rep.NewGraph(0.85, ε, results[NegConsumerID].PRank)
+		node := func(id string) rep.Node { return rep.NewNode(id, results[id].PRank, results[id].NRank) }
+		graph.AddPersonalizationNode(node("a"))
+		for _, link := range precisionLinks {
+			graph.Link(node(link.source), node(link.target), link.weight)
+		}
+		results = map[string]rep.Ranks{}
+		graph.Rank(func(id string, pRank float64, nRank float64) {
+			results[id] = rep.Ranks{ID: id, PRank: pRank, NRank: nRank}
+		})
+	}
+	return results
+}
+
+// rankDecimals ranks the precision graph twice with the given decimals
+func rankDecimals(decimals int, ε float64) map[string]Ranks {
+	results := map[string]Ranks{}
+	rank := func(id string) (Uint, Uint) {
+		if result, ok := results[id]; ok {
+			return result.PRank, result.NRank
+		}
+		return zero, zero
+	}
+	for pass := 0; pass < 2; pass++ {
+		negConsumerRank, _ := rank(NegConsumerID)
+		graph := NewGraphHelper(0.85, ε, negConsumerRank, WithDecimals(decimals))
+		node := func(id string) Node {
+			pRank, nRank := rank(id)
+			return NewNode(id, pRank, nRank)
+		}
+		graph.AddPersonalizationNode(node("a"))
+		for _, link := range precisionLinks {
+			graph.LinkHelper(node(link.source), node(link.target), link.weight)
+		}
+		results = rankResults(graph)
+	}
+	return results
+}
+
+func toFloat(u Uint, decimals int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(u.BigInt()), new(big.Float).SetInt(pow10(1, decimals).BigInt())).Float64()
+	return f
+}
+
+// maxError returns the largest difference between the ranks of rep and detrep
+func maxError(expected map[string]rep.Ranks, actual map[string]Ranks, decimals int) float64 {
+	var max float64
+	for id, e := range expected {
+		a := actual[id]
+		max = math.Max(max, math.Abs(e.PRank-toFloat(a.PRank, decimals)))
+		max = math.Max(max, math.Abs(e.NRank-toFloat(a.NRank, decimals)))
+	}
+	return max
+}
+
+func TestPrecision(t *testing.T) {
+	expected := rankRep(1e-14)
+
+	previous := math.Inf(1)
+	for _, decimals := range []int{4, 6, 9, 12, 15, 18} {
+		// ε can't be smaller than a single unit
+		ε := math.Max(1e-12, math.Pow(10, float64(2-decimals)))
+		actual := rankDecimals(decimals, ε)
+
+		if len(actual) != len(expected) {
+			t.Fatalf("decimals %d: expected %d results, got %d", decimals, len(expected), len(actual))
+		}
+		if !total(actual).Equal(pow10(1, decimals)) {
+			t.Errorf("decimals %d: ranks should add up to 1, got %s", decimals, total(actual))
+		}
+
+		// the error is a few units in the last decimal until it reaches the convergence error
+		err := maxError(expected, actual, decimals)
+		t.Logf("decimals %2d: max error %.3g", decimals, err)
+		if bound := math.Pow(10, float64(3-decimals)) + 1e-11; err > bound {
+			t.Errorf("decimals %d: error %g is larger than %g", decimals, err, bound)
+		}
+		if err > previous*1.1 {
+			t.Errorf("decimals %d: error %g should not be larger than with fewer decimals %g", decimals, err, previous)
+		}
+		previous = err
+	}
+}
+
+func TestWithDecimals(t *testing.T) {
+	graph := NewGraphHelper(0.85, 0.001, zero, WithDecimals(6))
+	if !graph.Precision.Equal(NewUint(1000000)) || !graph.MaxNegOffset.Equal(NewUint(10000000)) {
+		t.Errorf("unexpected precision %s and max neg offset %s", graph.Precision, graph.MaxNegOffset)
+	}
+	if !graph.Params.α.Equal(NewUint(850000)) || !graph.Params.ε.Equal(NewUint(1000)) {
+		t.Errorf("α and ε should use the graph decimals, got %s %s", graph.Params.α, graph.Params.ε)
+	}
+
+	for _, decimals := range []int{0, MaxDecimals + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for %d decimals", decimals)
+				}
+			}()
+			WithDecimals(decimals)
+		}()
+	}
+}
+
+func TestMinEpsilon(t *testing.T) {
+	// with 6 decimals an ε of 1e-6 is a single unit, less than the rounding error of an iteration
+	newGraph := func(ε float64) *Graph {
+		graph := NewGraphHelper(0.85, ε, zero, WithDecimals(6))
+		a, b, c := NewNodeInputHelper("a", 0, 0), NewNodeInputHelper("b", 0, 0), NewNodeInputHelper("c", 0, 0)
+		d, e := NewNodeInputHelper("d", 0, 0), NewNodeInputHelper("e", 0, 0)
+		graph.AddPersonalizationNode(a)
+		graph.LinkHelper(a, c, 2.0)
+		graph.LinkHelper(e, b, 2.0)
+		graph.LinkHelper(a, d, 2.0)
+		graph.MaxIterations = 1000
+		return graph
+	}
+
+	graph := newGraph(0.000001)
+	if err := graph.TryRank(func(string, Uint, Uint) {}); err == nil {
+		t.Error("expected a NotConvergedError with ε of a single unit")
+	}
+
+	// 6 nodes (with the negConsumer) and 3 edges
+	graph = newGraph(0.000001)
+	if !graph.MinEpsilon().Equal(NewUint(90)) {
+		t.Errorf("expected a min ε of 90 units, got %s", graph.MinEpsilon())
+	}
+	graph.Params.ε = graph.MinEpsilon()
+	if err := graph.TryRank(func(string, Uint, Uint) {}); err != nil {
+		t.Error(err)
+	}
+}
+
+// exactGraphs are small graphs to compare with the exact ranks, personalized by "a" if personalized is true
+var exactGraphs = []struct {
+	name         string
+	personalized bool
+	links        []testLink
+}{
+	{"circle", false, []testLink{{"a", "b", 1}, {"b", "c", 1}, {"c", "d", 1}, {"d", "a", 1}}},
+	{"weighted", false, []testLink{{"a", "b", 1}, {"a", "c", 3}, {"b", "c", 1}, {"c", "a", 2}, {"c", "d", 1}}},
+	{"personalized", true, []testLink{{"a", "b", 2}, {"a", "c", 1}, {"b", "c", 1}, {"c", "d", 1}, {"d", "b", 1}}},
+	{"negative", true, []testLink{{"a", "b", 2}, {"a", "c", 1}, {"c", "d", 1}, {"b", "d", -1}, {"a", "e", -1}, {"d", "e", 1}}},
+	{"cancelled", true, []testLink{{"a", "b", 2}, {"a", "b", -1}, {"a", "c", 1}, {"b", "c", -3}, {"b", "c", 1}, {"c", "a", 1}}},
+	{"negConsumer", true, precisionLinks},
+}
+
+// toRat returns u / Precision
+func toRat(u Uint, precision Uint) *big.Rat {
+	return new(big.Rat).SetFrac(u.BigInt(), precision.BigInt())
+}
+
+// rankExact returns the exact ranks of graph with the cached ranks of results
+func rankExact(t *testing.T, graph *Graph, personalized bool, links []testLink, results map[string]Ranks) map[string]exact.Result {
+	rank := func(id string) (*big.Rat, *big.Rat) {
+		if result, ok := results[id]; ok {
+			return toRat(result.PRank, graph.Precision), toRat(result.NRank, graph.Precision)
+		}
+		return new(big.Rat), new(big.Rat)
+	}
+	negConsumerRank, _ := rank(NegConsumerID)
+	exactGraph := exact.NewGraph(toRat(graph.Params.α, graph.Precision), negConsumerRank)
+	node := func(id string) exact.Node {
+		pRank, nRank := rank(id)
+		return exact.NewNode(id, pRank, nRank)
+	}
+	if personalized {
+		exactGraph.AddPersonalizationNode(node("a"))
+	}
+	for _, link := range links {
+		exactGraph.Link(node(link.source), node(link.target), new(big.Rat).SetFloat64(link.weight))
+	}
+	expected, err := exactGraph.Rank()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expected
+}
+
+// TestExact compares the ranks with the exact stationary vector of the same graph
+// the error is the convergence error of power iteration, at most about ε α / (1 - α),
+// plus a few units of rounding
+func TestExact(t *testing.T) {
+	α, ε := 0.85, 1e-10
+	for _, test := range exactGraphs {
+		results := map[string]Ranks{}
+		// the second pass uses the cached ranks of the first one
+		for pass := 0; pass < 2; pass++ {
+			rank := func(id string) (Uint, Uint) {
+				if result, ok := results[id]; ok {
+					return result.PRank, result.NRank
+				}
+				return zero, zero
+			}
+			negConsumerRank, _ := rank(NegConsumerID)
+			graph := NewGraphHelper(α, ε, negConsumerRank)
+			expected := rankExact(t, graph, test.personalized, test.links, results)
+
+			node := func(id string) Node {
+				pRank, nRank := rank(id)
+				return NewNode(id, pRank, nRank)
+			}
+			if test.personalized {
+				graph.AddPersonalizationNode(node("a"))
+			}
+			for _, link := range test.links {
+				graph.LinkHelper(node(link.source), node(link.target), link.weight)
+			}
+			results = rankResults(graph)
+
+			if len(results) != len(expected) {
+				t.Fatalf("%s pass %d: expected %d results, got %d", test.name, pass, len(expected), len(results))
+			}
+			worst := new(big.Rat)
+			for id, e := range expected {
+				for _, diff := range []*big.Rat{
+					new(big.Rat).Sub(toRat(results[id].PRank, graph.Precision), e.PRank),
+					new(big.Rat).Sub(toRat(results[id].NRank, graph.Precision), e.NRank),
+				} {
+					if diff.Abs(diff).Cmp(worst) > 0 {
+						worst = diff
+					}
+				}
+			}
+			f, _ := worst.Float64()
+			t.Logf("%s pass %d: max error %.3g", test.name, pass, f)
+			if bound := ε*α/(1-α) + 1e-15; f > bound {
+				t.Errorf("%s pass %d: error %g is larger than %g", test.name, pass, f, bound)
+			}
+		}
+	}
+}
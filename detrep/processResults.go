@@ -1,12 +1,28 @@
 package detrep
 
 import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"sort"
 )
 
-func (graph Graph) processResults(callback func(id string, pRank sdk.Uint, nRank sdk.Uint)) {
+// Ranks holds the computed ranks of a single node
+type Ranks struct {
+	ID    string `json:"id"`
+	PRank Uint   `json:"pRank"`
+	NRank Uint   `json:"nRank"`
+}
+
+// processResults merges negative nodes and calls the callback for every node in id order
+func (graph Graph) processResults(callback func(id string, pRank Uint, nRank Uint)) {
 	graph.mergeNegatives()
-	for key, node := range graph.Nodes {
+
+	keys := make([]string, 0, len(graph.Nodes))
+	for key := range graph.Nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		node := graph.Nodes[key]
 		callback(key, node.PRank, node.NRank)
 	}
 }
@@ -17,8 +33,8 @@ func (graph Graph) mergeNegatives() {
 		if _, ok := graph.Nodes[node.ID]; ok == false {
 			graph.Nodes[node.ID] = &Node{
 				ID:       key,
-				PRank:    sdk.ZeroUint(),
-				degree:   sdk.ZeroUint(),
+				PRank:    ZeroUint(),
+				degree:   ZeroUint(),
 				nodeType: Positive,
 			}
 		}
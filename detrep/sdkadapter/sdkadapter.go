@@ -0,0 +1,36 @@
+// Package sdkadapter converts between detrep numbers and cosmos sdk numbers
+// detrep doesn't depend on the cosmos sdk, the two types have the same range (256 bit Uint, 255 bit Int)
+// so values convert both ways without loss
+package sdkadapter
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/detrep"
+)
+
+// ToUint converts a detrep Uint to an sdk Uint
+func ToUint(u detrep.Uint) sdk.Uint {
+	return sdk.NewUintFromBigInt(u.BigInt())
+}
+
+// FromUint converts an sdk Uint to a detrep Uint
+func FromUint(u sdk.Uint) detrep.Uint {
+	return detrep.NewUintFromBigInt(u.BigInt())
+}
+
+// ToInt converts a detrep Int to an sdk Int
+func ToInt(i detrep.Int) sdk.Int {
+	return sdk.NewIntFromBigInt(i.BigInt())
+}
+
+// FromInt converts an sdk Int to a detrep Int
+func FromInt(i sdk.Int) detrep.Int {
+	return detrep.NewIntFromBigInt(i.BigInt())
+}
+
+// Callback adapts a Rank callback that takes sdk Uints
+func Callback(callback func(id string, pRank sdk.Uint, nRank sdk.Uint)) func(id string, pRank detrep.Uint, nRank detrep.Uint) {
+	return func(id string, pRank detrep.Uint, nRank detrep.Uint) {
+		callback(id, ToUint(pRank), ToUint(nRank))
+	}
+}
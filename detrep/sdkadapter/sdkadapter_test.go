@@ -0,0 +1,125 @@
+package sdkadapter
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/detrep"
+)
+
+// the cosmos gas meter can be used as a detrep Meter
+var _ detrep.Meter = sdk.NewGasMeter(0)
+
+func TestRoundTrip(t *testing.T) {
+	maxUint := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	for _, u := range []*big.Int{big.NewInt(0), big.NewInt(1e18), maxUint} {
+		v := detrep.NewUintFromBigInt(u)
+		if s := ToUint(v); s.BigInt().Cmp(u) != 0 || !FromUint(s).Equal(v) {
+			t.Errorf("%s did not round trip", u)
+		}
+	}
+
+	maxInt := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))
+	minInt := new(big.Int).Neg(maxInt)
+	for _, i := range []*big.Int{big.NewInt(0), big.NewInt(-1e18), maxInt, minInt} {
+		v := detrep.NewIntFromBigInt(i)
+		if s := ToInt(v); s.BigInt().Cmp(i) != 0 || !FromInt(s).Equal(v) {
+			t.Errorf("%s did not round trip", i)
+		}
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	max := ToUint(detrep.NewUintFromBigInt(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))))
+	panics := func(name string, f func()) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s should panic", name)
+			}
+		}()
+		f()
+	}
+	// both types reject the same values
+	panics("sdk add", func() { max.Add(sdk.OneUint()) })
+	panics("detrep add", func() { FromUint(max).Add(detrep.OneUint()) })
+	panics("sdk sub", func() { sdk.ZeroUint().Sub(sdk.OneUint()) })
+	panics("detrep sub", func() { detrep.ZeroUint().Sub(detrep.OneUint()) })
+}
+
+func TestEncoding(t *testing.T) {
+	u := detrep.FtoBD(0.123456789)
+	native, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cosmos, err := json.Marshal(ToUint(u))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(native) != string(cosmos) {
+		t.Errorf("expected json %s got %s", cosmos, native)
+	}
+
+	var decoded detrep.Uint
+	if err := json.Unmarshal(cosmos, &decoded); err != nil || !decoded.Equal(u) {
+		t.Errorf("could not decode sdk json %s", cosmos)
+	}
+
+	bz, err := ToUint(u).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Unmarshal(bz); err != nil || !decoded.Equal(u) {
+		t.Errorf("could not decode sdk bytes %s", bz)
+	}
+}
+
+func testGraph() *detrep.Graph {
+	graph := detrep.NewGraphHelper(0.85, 0.000001, detrep.ZeroUint())
+	a := detrep.NewNodeInputHelper("a", 0, 0)
+	b := detrep.NewNodeInputHelper("b", 0, 0)
+	c := detrep.NewNodeInputHelper("c", 0, 0)
+	d := detrep.NewNodeInputHelper("d", 0, 0)
+
+	graph.AddPersonalizationNode(a)
+	graph.LinkHelper(a, b, 1.0)
+	graph.LinkHelper(a, c, 2.0)
+	graph.LinkHelper(b, d, -1.0)
+	graph.LinkHelper(c, d, 1.0)
+	return graph
+}
+
+func TestCallback(t *testing.T) {
+	expected := map[string]detrep.Ranks{}
+	testGraph().Rank(func(id string, pRank detrep.Uint, nRank detrep.Uint) {
+		expected[id] = detrep.Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+
+	actual := map[string][2]sdk.Uint{}
+	testGraph().Rank(Callback(func(id string, pRank sdk.Uint, nRank sdk.Uint) {
+		actual[id] = [2]sdk.Uint{pRank, nRank}
+	}))
+
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(actual))
+	}
+	for id, e := range expected {
+		a := actual[id]
+		if !FromUint(a[0]).Equal(e.PRank) || !FromUint(a[1]).Equal(e.NRank) {
+			t.Errorf("%s expected %v got %v", id, e, a)
+		}
+	}
+}
+
+func TestSdkGasMeter(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(sdk.ErrorOutOfGas); !ok {
+			t.Errorf("expected the sdk meter to panic with ErrorOutOfGas")
+		}
+	}()
+	graph := testGraph()
+	graph.Meter = sdk.NewGasMeter(1000)
+	graph.TryRank(func(string, detrep.Uint, detrep.Uint) {})
+}
@@ -2,18 +2,27 @@ package detrep
 
 import (
 	"math"
-
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"math/big"
 )
 
 // FtoBD converts a float64 to a Uint with decimals (i * 10^decimals)
-func FtoBD(n float64) sdk.Uint {
-	return sdk.NewUint(uint64(n * math.Pow(10, float64(Decimals))))
+func FtoBD(n float64) Uint {
+	return FtoBDWithDecimals(n, Decimals)
+}
+
+// FtoBDWithDecimals converts a float64 to a Uint with the given decimals
+func FtoBDWithDecimals(n float64, decimals int) Uint {
+	i, _ := big.NewFloat(n * math.Pow(10, float64(decimals))).Int(nil)
+	return NewUintFromBigInt(i)
 }
 
 // NewGraphHelper is test helper that allows use of floats
-func NewGraphHelper(α, ε float64, negConsumerRank sdk.Uint) *Graph {
-	return NewGraph(FtoBD(α), FtoBD(ε), negConsumerRank)
+// α and ε are converted with the decimals of the graph
+func NewGraphHelper(α, ε float64, negConsumerRank Uint, options ...Option) *Graph {
+	graph := NewGraph(ZeroUint(), ZeroUint(), negConsumerRank, options...)
+	graph.Params.α = FtoBDWithDecimals(α, graph.Decimals)
+	graph.Params.ε = FtoBDWithDecimals(ε, graph.Decimals)
+	return graph
 }
 
 // NewNodeInputHelper is test helper that allows use of floats
@@ -23,7 +32,8 @@ func NewNodeInputHelper(id string, pRank float64, nRank float64) Node {
 
 // LinkHelper is test helper that allows use of floats
 func (graph Graph) LinkHelper(source, target Node, weight float64) {
-	weightInt := sdk.NewInt(int64(weight * math.Pow(10, float64(Decimals/2))))
-	weightInt = weightInt.Mul(sdk.NewInt(int64(math.Pow(10, float64(Decimals/2)))))
+	half := graph.Decimals / 2
+	weightInt := NewInt(int64(weight * math.Pow(10, float64(half))))
+	weightInt = weightInt.Mul(NewInt(int64(math.Pow(10, float64(graph.Decimals-half)))))
 	graph.Link(source, target, weightInt)
 }
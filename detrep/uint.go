@@ -0,0 +1,294 @@
+package detrep
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// the largest supported number of bits, operations panic beyond them
+// they match the limits of the cosmos sdk Uint and Int so values can be converted both ways
+const (
+	maxBitLen    = 256
+	maxIntBitLen = 255
+)
+
+// Uint is an immutable unsigned fixed-point number
+// every operation returns a new value and panics on overflow, underflow and division by zero
+type Uint struct {
+	i *big.Int
+}
+
+// NewUint returns a Uint from a uint64
+func NewUint(n uint64) Uint {
+	return Uint{new(big.Int).SetUint64(n)}
+}
+
+// NewUintFromBigInt returns a Uint from a big.Int, it panics if i is negative or too large
+func NewUintFromBigInt(i *big.Int) Uint {
+	return checkUint(new(big.Int).Set(i))
+}
+
+// NewUintFromString parses a decimal Uint
+func NewUintFromString(s string) (Uint, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok || i.Sign() < 0 || i.BitLen() > maxBitLen {
+		return Uint{}, fmt.Errorf("invalid Uint %q", s)
+	}
+	return Uint{i}, nil
+}
+
+// ZeroUint returns 0
+func ZeroUint() Uint { return NewUint(0) }
+
+// OneUint returns 1
+func OneUint() Uint { return NewUint(1) }
+
+// pow10 returns n * 10^decimals
+func pow10(n int64, decimals int) Uint {
+	exp := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return checkUint(exp.Mul(exp, big.NewInt(n)))
+}
+
+func checkUint(i *big.Int) Uint {
+	if i.Sign() < 0 {
+		panic("Uint underflow")
+	}
+	if i.BitLen() > maxBitLen {
+		panic("Uint overflow")
+	}
+	return Uint{i}
+}
+
+// BigInt returns a copy of the value as a big.Int
+func (u Uint) BigInt() *big.Int { return new(big.Int).Set(u.i) }
+
+// Uint64 returns the value as a uint64, it panics if it doesn't fit
+func (u Uint) Uint64() uint64 {
+	if !u.i.IsUint64() {
+		panic("Uint64() out of bound")
+	}
+	return u.i.Uint64()
+}
+
+// IsZero returns true if the value is 0
+func (u Uint) IsZero() bool { return u.i.Sign() == 0 }
+
+// Equal returns true if u == v
+func (u Uint) Equal(v Uint) bool { return u.i.Cmp(v.i) == 0 }
+
+// GT returns true if u > v
+func (u Uint) GT(v Uint) bool { return u.i.Cmp(v.i) > 0 }
+
+// GTE returns true if u >= v
+func (u Uint) GTE(v Uint) bool { return u.i.Cmp(v.i) >= 0 }
+
+// LT returns true if u < v
+func (u Uint) LT(v Uint) bool { return u.i.Cmp(v.i) < 0 }
+
+// LTE returns true if u <= v
+func (u Uint) LTE(v Uint) bool { return u.i.Cmp(v.i) <= 0 }
+
+// Add returns u + v
+func (u Uint) Add(v Uint) Uint { return checkUint(new(big.Int).Add(u.i, v.i)) }
+
+// Sub returns u - v, it panics if v > u
+func (u Uint) Sub(v Uint) Uint { return checkUint(new(big.Int).Sub(u.i, v.i)) }
+
+// Mul returns u * v
+func (u Uint) Mul(v Uint) Uint { return checkUint(new(big.Int).Mul(u.i, v.i)) }
+
+// MulUint64 returns u * v
+func (u Uint) MulUint64(v uint64) Uint { return u.Mul(NewUint(v)) }
+
+// Quo returns u / v rounded down
+func (u Uint) Quo(v Uint) Uint {
+	if v.IsZero() {
+		panic("division by zero")
+	}
+	return Uint{new(big.Int).Quo(u.i, v.i)}
+}
+
+// QuoUint64 returns u / v rounded down
+func (u Uint) QuoUint64(v uint64) Uint { return u.Quo(NewUint(v)) }
+
+// Mod returns u % v
+func (u Uint) Mod(v Uint) Uint {
+	if v.IsZero() {
+		panic("division by zero")
+	}
+	return Uint{new(big.Int).Rem(u.i, v.i)}
+}
+
+// MinUint returns the smallest of u and v
+func MinUint(u, v Uint) Uint {
+	if u.LT(v) {
+		return u
+	}
+	return v
+}
+
+// String returns the decimal representation of the value
+func (u Uint) String() string { return u.i.String() }
+
+// Marshal returns the decimal representation of the value as bytes
+func (u Uint) Marshal() ([]byte, error) {
+	if u.i == nil {
+		return []byte("0"), nil
+	}
+	return u.i.MarshalText()
+}
+
+// Unmarshal parses the output of Marshal
+func (u *Uint) Unmarshal(bz []byte) error {
+	parsed, err := NewUintFromString(string(bz))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// MarshalJSON encodes the value as a decimal string
+func (u Uint) MarshalJSON() ([]byte, error) {
+	bz, err := u.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(bz))
+}
+
+// UnmarshalJSON decodes a decimal string
+func (u *Uint) UnmarshalJSON(bz []byte) error {
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return err
+	}
+	return u.Unmarshal([]byte(s))
+}
+
+// Int is an immutable signed fixed-point number, it is used for signed link weights
+// every operation returns a new value and panics on overflow
+type Int struct {
+	i *big.Int
+}
+
+// NewInt returns an Int from an int64
+func NewInt(n int64) Int {
+	return Int{big.NewInt(n)}
+}
+
+// NewIntFromBigInt returns an Int from a big.Int, it panics if i is too large
+func NewIntFromBigInt(i *big.Int) Int {
+	return checkInt(new(big.Int).Set(i))
+}
+
+// NewIntFromString parses a decimal Int
+func NewIntFromString(s string) (Int, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok || i.BitLen() > maxIntBitLen {
+		return Int{}, fmt.Errorf("invalid Int %q", s)
+	}
+	return Int{i}, nil
+}
+
+// ZeroInt returns 0
+func ZeroInt() Int { return NewInt(0) }
+
+func checkInt(i *big.Int) Int {
+	if i.BitLen() > maxIntBitLen {
+		panic("Int overflow")
+	}
+	return Int{i}
+}
+
+// BigInt returns a copy of the value as a big.Int
+func (i Int) BigInt() *big.Int { return new(big.Int).Set(i.i) }
+
+// Sign returns -1, 0 or 1
+func (i Int) Sign() int { return i.i.Sign() }
+
+// IsZero returns true if the value is 0
+func (i Int) IsZero() bool { return i.i.Sign() == 0 }
+
+// IsNegative returns true if the value is smaller than 0
+func (i Int) IsNegative() bool { return i.i.Sign() < 0 }
+
+// Equal returns true if i == j
+func (i Int) Equal(j Int) bool { return i.i.Cmp(j.i) == 0 }
+
+// GT returns true if i > j
+func (i Int) GT(j Int) bool { return i.i.Cmp(j.i) > 0 }
+
+// LT returns true if i < j
+func (i Int) LT(j Int) bool { return i.i.Cmp(j.i) < 0 }
+
+// Add returns i + j
+func (i Int) Add(j Int) Int { return checkInt(new(big.Int).Add(i.i, j.i)) }
+
+// Sub returns i - j
+func (i Int) Sub(j Int) Int { return checkInt(new(big.Int).Sub(i.i, j.i)) }
+
+// Mul returns i * j
+func (i Int) Mul(j Int) Int { return checkInt(new(big.Int).Mul(i.i, j.i)) }
+
+// Quo returns i / j rounded towards 0
+func (i Int) Quo(j Int) Int {
+	if j.IsZero() {
+		panic("division by zero")
+	}
+	return Int{new(big.Int).Quo(i.i, j.i)}
+}
+
+// Int64 returns the value as an int64, it panics if it doesn't fit
+func (i Int) Int64() int64 {
+	if !i.i.IsInt64() {
+		panic("Int64() out of bound")
+	}
+	return i.i.Int64()
+}
+
+// Neg returns -i
+func (i Int) Neg() Int { return Int{new(big.Int).Neg(i.i)} }
+
+// Abs returns the absolute value of i as a Uint
+func (i Int) Abs() Uint { return Uint{new(big.Int).Abs(i.i)} }
+
+// String returns the decimal representation of the value
+func (i Int) String() string { return i.i.String() }
+
+// Marshal returns the decimal representation of the value as bytes
+func (i Int) Marshal() ([]byte, error) {
+	if i.i == nil {
+		return []byte("0"), nil
+	}
+	return i.i.MarshalText()
+}
+
+// Unmarshal parses the output of Marshal
+func (i *Int) Unmarshal(bz []byte) error {
+	parsed, err := NewIntFromString(string(bz))
+	if err != nil {
+		return err
+	}
+	*i = parsed
+	return nil
+}
+
+// MarshalJSON encodes the value as a decimal string
+func (i Int) MarshalJSON() ([]byte, error) {
+	bz, err := i.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(bz))
+}
+
+// UnmarshalJSON decodes a decimal string
+func (i *Int) UnmarshalJSON(bz []byte) error {
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return err
+	}
+	return i.Unmarshal([]byte(s))
+}
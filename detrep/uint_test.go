@@ -0,0 +1,70 @@
+package detrep
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func expectPanic(t *testing.T, name string, f func()) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s should panic", name)
+		}
+	}()
+	f()
+}
+
+func TestUintBounds(t *testing.T) {
+	max := NewUintFromBigInt(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), maxBitLen), big.NewInt(1)))
+	expectPanic(t, "overflow", func() { max.Add(OneUint()) })
+	expectPanic(t, "underflow", func() { ZeroUint().Sub(OneUint()) })
+	expectPanic(t, "division by zero", func() { OneUint().Quo(ZeroUint()) })
+	expectPanic(t, "negative big.Int", func() { NewUintFromBigInt(big.NewInt(-1)) })
+
+	if _, err := NewUintFromString("-1"); err == nil {
+		t.Errorf("expected an error parsing a negative Uint")
+	}
+	if u, err := NewUintFromString(max.String()); err != nil || !u.Equal(max) {
+		t.Errorf("could not parse %s", max)
+	}
+}
+
+func TestUintImmutable(t *testing.T) {
+	a := NewUint(2)
+	b := a.Add(OneUint())
+	if !a.Equal(NewUint(2)) || !b.Equal(NewUint(3)) {
+		t.Errorf("operations should not modify their receiver")
+	}
+	i := big.NewInt(5)
+	u := NewUintFromBigInt(i)
+	i.SetInt64(6)
+	if !u.Equal(NewUint(5)) {
+		t.Errorf("Uint should not share the big.Int it was created from")
+	}
+}
+
+func TestUintJSON(t *testing.T) {
+	u := FtoBD(0.5)
+	bz, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bz) != `"500000000000000000"` {
+		t.Errorf("unexpected json %s", bz)
+	}
+	var decoded Uint
+	if err := json.Unmarshal(bz, &decoded); err != nil || !decoded.Equal(u) {
+		t.Errorf("could not decode %s", bz)
+	}
+
+	w := NewInt(-42)
+	bz, err = json.Marshal(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decodedInt Int
+	if err := json.Unmarshal(bz, &decodedInt); err != nil || !decodedInt.Equal(w) {
+		t.Errorf("could not decode %s", bz)
+	}
+}
@@ -0,0 +1,99 @@
+package detrep
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClaimRejected is returned by Verify when the claimed results are not a fixed point of the graph
+var ErrClaimRejected = errors.New("claimed results rejected")
+
+// Verification describes how close claimed results are to a fixed point of the graph
+// all values use the graph precision
+type Verification struct {
+	Residual Uint // sum of |rank after one iteration - claimed rank| over all nodes
+	Total    Uint // sum of all claimed ranks, positive and negative
+}
+
+// Verify checks claimed results (as returned by Rank) against a graph without ranking it
+// it runs a single iteration starting from the claimed ranks and checks that
+// - every node of the graph has a claimed result and there are no unknown ones
+// - every rank is between 0 and Precision
+// - the total rank is within tolerance of Precision (no mass was created or lost)
+// - the residual of the iteration is within tolerance
+// the graph is finalized and normalized like in Rank, so it can't be ranked or verified again
+func Verify(graph *Graph, claimed map[string]Ranks, tolerance Uint) (Verification, error) {
+	state, iterationGas := graph.prepare()
+
+	// results are keyed by id, the graph by positive and negative keys
+	expected := map[string]bool{}
+	for key, node := range graph.Nodes {
+		expected[node.ID] = true
+		if node.nodeType == Positive {
+			expected[key] = true
+		}
+	}
+	for id := range expected {
+		if _, ok := claimed[id]; !ok {
+			return Verification{}, fmt.Errorf("%w: missing result for %s", ErrClaimRejected, id)
+		}
+	}
+
+	total := ZeroUint()
+	for id, result := range claimed {
+		if !expected[id] {
+			return Verification{}, fmt.Errorf("%w: unknown node %s", ErrClaimRejected, id)
+		}
+		for _, rank := range []Uint{result.PRank, result.NRank} {
+			if rank == (Uint{}) {
+				return Verification{}, fmt.Errorf("%w: missing rank for %s", ErrClaimRejected, id)
+			}
+			if rank.BigInt().Sign() < 0 || rank.GT(graph.Precision) {
+				return Verification{}, fmt.Errorf("%w: rank of %s is out of range: %s", ErrClaimRejected, id, rank)
+			}
+			total = total.Add(rank)
+		}
+	}
+	if absDiff(total, graph.Precision).GT(tolerance) {
+		return Verification{Total: total}, fmt.Errorf("%w: total rank %s is not within %s of %s", ErrClaimRejected, total, tolerance, graph.Precision)
+	}
+
+	for key, node := range graph.Nodes {
+		if node.nodeType == Negative {
+			node.PRank = claimed[node.ID].NRank
+		} else {
+			node.PRank = claimed[key].PRank
+		}
+	}
+
+	graph.consumeGas(iterationGas, GasDescIteration)
+	state.acc.load(graph, state.keys)
+	graph.iterate(state)
+	state.acc.store(graph, state.keys)
+
+	// ranks the graph can't produce, like the NRank of a node without negative links, must be 0
+	residual := ZeroUint()
+	for id, result := range claimed {
+		pRank, nRank := ZeroUint(), ZeroUint()
+		if node, ok := graph.Nodes[id]; ok && node.nodeType == Positive {
+			pRank = node.PRank
+		}
+		if node, ok := graph.Nodes[getKey(id, Negative)]; ok {
+			nRank = node.PRank
+		}
+		residual = residual.Add(absDiff(pRank, result.PRank)).Add(absDiff(nRank, result.NRank))
+	}
+
+	verification := Verification{Residual: residual, Total: total}
+	if residual.GT(tolerance) {
+		return verification, fmt.Errorf("%w: residual %s is larger than %s", ErrClaimRejected, residual, tolerance)
+	}
+	return verification, nil
+}
+
+func absDiff(a, b Uint) Uint {
+	if a.LT(b) {
+		return b.Sub(a)
+	}
+	return a.Sub(b)
+}
@@ -0,0 +1,116 @@
+package detrep
+
+import (
+	"errors"
+	"testing"
+)
+
+func verifyLinks(graph *Graph, n map[string]Node) {
+	graph.LinkHelper(n["a"], n["b"], 2.0)
+	graph.LinkHelper(n["a"], n["c"], 1.0)
+	graph.LinkHelper(n["b"], n["c"], -1.0)
+	graph.LinkHelper(n["b"], n["a"], 1.0)
+	graph.LinkHelper(n["c"], n["d"], -1.0)
+	graph.LinkHelper(n["d"], n["e"], 3.0)
+}
+
+// unrankedGraph builds the graph ranked by rankTwice with results as cached ranks
+func unrankedGraph(results map[string]Ranks, links func(graph *Graph, nodes map[string]Node)) *Graph {
+	graph := NewGraphHelper(0.85, 0.000001, results[NegConsumerID].PRank)
+	nodes := map[string]Node{}
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		nodes[id] = NewNode(id, results[id].PRank, results[id].NRank)
+	}
+	graph.AddPersonalizationNode(nodes["a"])
+	links(graph, nodes)
+	return graph
+}
+
+func copyResults(results map[string]Ranks) map[string]Ranks {
+	copied := map[string]Ranks{}
+	for id, result := range results {
+		copied[id] = result
+	}
+	return copied
+}
+
+func TestVerify(t *testing.T) {
+	_, results := rankTwice(verifyLinks)
+	tolerance := FtoBD(0.00001)
+
+	v, err := Verify(unrankedGraph(results, verifyLinks), results, tolerance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Residual.GT(tolerance) || absDiff(v.Total, FtoBD(1)).GT(tolerance) {
+		t.Errorf("unexpected verification %v", v)
+	}
+
+	// rank moved from one node to another keeps the total but is not a fixed point
+	moved := copyResults(results)
+	moved["b"] = Ranks{ID: "b", PRank: results["b"].PRank.Sub(FtoBD(0.01)), NRank: results["b"].NRank}
+	moved["e"] = Ranks{ID: "e", PRank: results["e"].PRank.Add(FtoBD(0.01)), NRank: results["e"].NRank}
+	if _, err := Verify(unrankedGraph(results, verifyLinks), moved, tolerance); !errors.Is(err, ErrClaimRejected) {
+		t.Error("results that are not a fixed point should be rejected")
+	}
+
+	// a node without negative links can't have a negative rank
+	negative := copyResults(results)
+	negative["b"] = Ranks{ID: "b", PRank: results["b"].PRank.Sub(FtoBD(0.01)), NRank: FtoBD(0.01)}
+	if _, err := Verify(unrankedGraph(results, verifyLinks), negative, tolerance); !errors.Is(err, ErrClaimRejected) {
+		t.Error("a negative rank without negative links should be rejected")
+	}
+
+	// half of the rank is missing
+	scaled := map[string]Ranks{}
+	for id, result := range results {
+		scaled[id] = Ranks{ID: id, PRank: result.PRank.QuoUint64(2), NRank: result.NRank.QuoUint64(2)}
+	}
+	if _, err := Verify(unrankedGraph(results, verifyLinks), scaled, tolerance); !errors.Is(err, ErrClaimRejected) {
+		t.Error("results that don't add up to 1 should be rejected")
+	}
+}
+
+func TestVerifyNotPersonalized(t *testing.T) {
+	build := func() *Graph {
+		graph := NewGraphHelper(0.85, 0.000001, zero)
+		a, b, c := NewNodeInputHelper("a", 0, 0), NewNodeInputHelper("b", 0, 0), NewNodeInputHelper("c", 0, 0)
+		graph.LinkHelper(a, b, 1.0)
+		graph.LinkHelper(b, c, 2.0)
+		graph.LinkHelper(c, a, 1.0)
+		graph.LinkHelper(a, c, 1.0)
+		return graph
+	}
+	results := map[string]Ranks{}
+	build().Rank(func(id string, pRank Uint, nRank Uint) {
+		results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	if _, err := Verify(build(), results, FtoBD(0.00001)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	_, results := rankTwice(verifyLinks)
+	tolerance := FtoBD(0.00001)
+
+	missing := copyResults(results)
+	delete(missing, "d")
+	unknown := copyResults(results)
+	unknown["x"] = Ranks{ID: "x", PRank: zero, NRank: zero}
+	tooLarge := copyResults(results)
+	tooLarge["e"] = Ranks{ID: "e", PRank: FtoBD(1).Add(OneUint()), NRank: zero}
+	empty := copyResults(results)
+	empty["e"] = Ranks{ID: "e"}
+
+	for name, claimed := range map[string]map[string]Ranks{
+		"missing":   missing,
+		"unknown":   unknown,
+		"too large": tooLarge,
+		"empty":     empty,
+	} {
+		if _, err := Verify(unrankedGraph(results, verifyLinks), claimed, tolerance); !errors.Is(err, ErrClaimRejected) {
+			t.Errorf("%s: expected the claim to be rejected, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package eigentrust ranks signed graphs with EigenTrust
+// (Kamvar, Schlosser and Garcia-Molina, The EigenTrust Algorithm for Reputation Management in P2P Networks, 2003)
+// it takes the same links and personalization nodes as rep.Graph and returns results in the same format,
+// so the two ways of handling negative links can be compared on the same data.
+//
+// the local trust of i in j is the sum of the positive minus the negative links from i to j, clipped at 0,
+// and normalized so the trust of i adds up to 1. negative links only cancel positive ones, they don't
+// propagate distrust and nodes have no negative rank
+package eigentrust
+
+import (
+	"math"
+	"sort"
+
+	"github.com/relevant-community/reputation/rep"
+)
+
+var _ rep.Linker = (*Graph)(nil)
+
+// Graph holds the signed local trust between peers
+type Graph struct {
+	α, ε        float64
+	preTrusted  []string
+	nodes       map[string]bool
+	interaction map[string]map[string]float64 // sum of positive minus negative links
+}
+
+// NewGraph returns an empty graph
+// α is the weight of the trust of other peers, 1 - α goes to the pre-trusted peers (a = 1 - α in the paper)
+// ε is the convergence criteria, like in rep
+func NewGraph(α, ε float64) *Graph {
+	return &Graph{
+		α:           α,
+		ε:           ε,
+		preTrusted:  []string{},
+		nodes:       map[string]bool{},
+		interaction: map[string]map[string]float64{},
+	}
+}
+
+// FromSnapshot builds a graph with the params, personalization and links of a rep snapshot
+// cached ranks are not used
+func FromSnapshot(snapshot rep.Snapshot) *Graph {
+	graph := NewGraph(snapshot.Alpha, snapshot.Epsilon)
+	snapshot.Load(graph)
+	return graph
+}
+
+// AddPersonalizationNode adds a pre-trusted peer
+// pre-trusted peers share the random jumps equally, unlike rep they are not weighted by degree
+func (graph *Graph) AddPersonalizationNode(pNode rep.Node) {
+	graph.preTrusted = append(graph.preTrusted, pNode.ID)
+	graph.nodes[pNode.ID] = true
+}
+
+// Link adds a signed interaction from source to target, negative weights are negative interactions
+// the cached ranks of the nodes are ignored
+func (graph *Graph) Link(source, target rep.Node, weight float64) {
+	graph.nodes[source.ID] = true
+	graph.nodes[target.ID] = true
+	if _, ok := graph.interaction[source.ID]; !ok {
+		graph.interaction[source.ID] = map[string]float64{}
+	}
+	graph.interaction[source.ID][target.ID] += weight
+}
+
+// trustEdge is the normalized local trust of a peer in target
+type trustEdge struct {
+	target int
+	trust  float64
+}
+
+// Rank computes the global trust of every node and calls callback for each one
+// the negative rank is always 0
+//
+// peers that trust nobody (or only have negative interactions) trust the pre-trusted peers,
+// or every peer if there are none
+func (graph *Graph) Rank(callback func(id string, pRank float64, nRank float64)) {
+	keys := make([]string, 0, len(graph.nodes))
+	for key := range graph.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	// p is the pre-trust distribution
+	p := make([]float64, len(keys))
+	if len(graph.preTrusted) == 0 {
+		for i := range p {
+			p[i] = 1 / float64(len(p))
+		}
+	} else {
+		for _, id := range graph.preTrusted {
+			p[index[id]] += 1 / float64(len(graph.preTrusted))
+		}
+	}
+
+	trust := graph.localTrust(keys, index)
+
+	α := graph.α
+	t := append([]float64{}, p...)
+	next := make([]float64, len(t))
+	for Δ := 1.0; Δ > graph.ε; {
+		var untrusted float64
+		for i := range next {
+			next[i] = 0
+		}
+		for i, edges := range trust {
+			if len(edges) == 0 {
+				untrusted += t[i]
+				continue
+			}
+			for _, edge := range edges {
+				next[edge.target] += α * t[i] * edge.trust
+			}
+		}
+		for i := range next {
+			next[i] += (1 - α + α*untrusted) * p[i]
+		}
+
+		Δ = 0
+		for i := range t {
+			Δ += math.Abs(next[i] - t[i])
+		}
+		t, next = next, t
+	}
+
+	for i, key := range keys {
+		callback(key, t[i], 0)
+	}
+}
+
+// localTrust returns the normalized local trust of every peer, indexed like keys
+// self trust is ignored
+func (graph *Graph) localTrust(keys []string, index map[string]int) [][]trustEdge {
+	trust := make([][]trustEdge, len(keys))
+	for i, key := range keys {
+		for target, s := range graph.interaction[key] {
+			if s > 0 && target != key {
+				trust[i] = append(trust[i], trustEdge{target: index[target], trust: s})
+			}
+		}
+		// the interactions come from a map, sorted by target the local trust adds up the same way every time
+		sort.Slice(trust[i], func(a, b int) bool { return trust[i][a].target < trust[i][b].target })
+
+		var sum float64
+		for _, edge := range trust[i] {
+			sum += edge.trust
+		}
+		for j := range trust[i] {
+			trust[i][j].trust /= sum
+		}
+	}
+	return trust
+}
@@ -0,0 +1,73 @@
+package eigentrust
+
+import (
+	"testing"
+
+	"github.com/relevant-community/reputation/internal/ranktest"
+	"github.com/relevant-community/reputation/rep"
+)
+
+var (
+	node = ranktest.Node
+	rank = ranktest.Rank
+)
+
+// expectRanks checks the global trust of every node, the negative ranks are always 0
+func expectRanks(t *testing.T, results map[string]rep.Ranks, expected map[string]float64) {
+	t.Helper()
+	ranks := map[string][2]float64{}
+	for id, e := range expected {
+		ranks[id] = [2]float64{e, 0}
+	}
+	ranktest.ExpectRanks(t, results, ranks)
+}
+
+func TestEmpty(t *testing.T) {
+	ranktest.ExpectEmpty(t, NewGraph(0.85, 1e-12))
+}
+
+func TestClipped(t *testing.T) {
+	// the negative link cancels a -> c, c gets no trust
+	// a = 1/2 + 1/2 b, b = 1/2 a => a = 2/3, b = 1/3
+	graph := NewGraph(0.5, 1e-12)
+	graph.AddPersonalizationNode(node("a"))
+	graph.Link(node("a"), node("b"), 1)
+	graph.Link(node("a"), node("c"), 1)
+	graph.Link(node("a"), node("c"), -1)
+	graph.Link(node("b"), node("a"), 1)
+	graph.Link(node("c"), node("a"), 1)
+
+	expectRanks(t, rank(graph), map[string]float64{"a": 2.0 / 3, "b": 1.0 / 3, "c": 0})
+}
+
+func TestDistrustOnly(t *testing.T) {
+	// a only distrusts b, so it trusts the pre-trusted peers: all of the trust stays with a
+	graph := NewGraph(0.85, 1e-12)
+	graph.AddPersonalizationNode(node("a"))
+	graph.Link(node("a"), node("b"), 1)
+	graph.Link(node("a"), node("b"), -3)
+
+	expectRanks(t, rank(graph), map[string]float64{"a": 1, "b": 0})
+}
+
+func TestPositiveLinksMatchRep(t *testing.T) {
+	// with a single seed and no negative links EigenTrust is personalized pagerank
+	ranktest.ExpectRepRanks(t, NewGraph(0.85, 1e-12), rep.NewGraph(0.85, 1e-12, 0), []string{"a"}, ranktest.PositiveLinks)
+}
+
+func TestFromSnapshot(t *testing.T) {
+	snapshot := rep.Snapshot{
+		Alpha:           0.5,
+		Epsilon:         1e-12,
+		Personalization: []string{"a"},
+		Links: []rep.SnapshotLink{
+			{Source: "a", Target: "b", Weight: 1},
+			{Source: "a", Target: "c", Weight: 1},
+			{Source: "a", Target: "c", Weight: -1},
+			{Source: "b", Target: "a", Weight: 1},
+			{Source: "c", Target: "a", Weight: 1},
+		},
+	}
+	// same as TestClipped
+	expectRanks(t, rank(FromSnapshot(snapshot)), map[string]float64{"a": 2.0 / 3, "b": 1.0 / 3, "c": 0})
+}
@@ -0,0 +1,212 @@
+// Package exact computes the exact ranks of small reputation graphs with rational numbers
+// it builds the graph like rep and detrep (negative nodes, cancelled opposite links and the negConsumer)
+// and solves for the stationary vector with Gaussian elimination instead of iterating.
+// it is meant to be used as a reference in tests, the cost grows with the cube of the number of nodes
+package exact
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// NodeType is positive or negative
+type NodeType int
+
+// Positive nodes are consumers of positive links
+// Negative nodes are consumers of neg links
+const (
+	Positive NodeType = iota
+	Negative
+)
+
+// MaxNegOffset is the cutoff for counting the outgoing links of a node, see rep.MaxNegOffset
+const MaxNegOffset = 10
+
+// NegConsumerID is the id of the node that consumes the outgoing weight of nodes with a negative rank
+const NegConsumerID = "negConsumer"
+
+// Node is a node input with its cached ranks
+type Node struct {
+	ID    string
+	PRank *big.Rat
+	NRank *big.Rat
+}
+
+type node struct {
+	id       string
+	rank     *big.Rat // cached rank
+	degree   *big.Rat
+	nodeType NodeType
+}
+
+// Graph holds node and edge data
+type Graph struct {
+	α               *big.Rat
+	personalization []string
+	negConsumerRank *big.Rat
+	nodes           map[string]*node
+	negNodes        map[string]*node
+	edges           map[string]map[string]*big.Rat
+}
+
+// NewGraph returns an empty graph, α and the cached negConsumer rank are copied
+func NewGraph(α *big.Rat, negConsumerRank *big.Rat) *Graph {
+	return &Graph{
+		α:               new(big.Rat).Set(α),
+		personalization: []string{},
+		negConsumerRank: new(big.Rat).Set(negConsumerRank),
+		nodes:           map[string]*node{},
+		negNodes:        map[string]*node{},
+		edges:           map[string]map[string]*big.Rat{},
+	}
+}
+
+// NewNode returns a node input, a nil rank is 0
+func NewNode(id string, pRank, nRank *big.Rat) Node {
+	if pRank == nil {
+		pRank = new(big.Rat)
+	}
+	if nRank == nil {
+		nRank = new(big.Rat)
+	}
+	return Node{ID: id, PRank: pRank, NRank: nRank}
+}
+
+// AddPersonalizationNode adds a node to the personalization vector
+func (graph *Graph) AddPersonalizationNode(pNode Node) {
+	graph.personalization = append(graph.personalization, pNode.ID)
+	graph.initNode(pNode.ID, pNode, Positive)
+}
+
+// Link creates a weighted edge between a source-target node pair, negative weights are negative links
+// links are added and cancelled exactly like in rep.Graph.Link
+func (graph *Graph) Link(source, target Node, weight *big.Rat) {
+	// if a node's neg/pos rank ratio is too high we don't process its links
+	if source.PRank.Sign() > 0 && new(big.Rat).Quo(source.NRank, source.PRank).Cmp(maxNegRatio()) > 0 {
+		return
+	}
+
+	sourceNode := graph.initNode(source.ID, source, Positive)
+
+	nodeType := Positive
+	if weight.Sign() < 0 {
+		nodeType = Negative
+	}
+	targetKey := getKey(target.ID, nodeType)
+	graph.initNode(targetKey, target, nodeType)
+
+	abs := new(big.Rat).Abs(weight)
+	if _, ok := graph.edges[source.ID]; !ok {
+		graph.edges[source.ID] = map[string]*big.Rat{}
+	}
+	if _, ok := graph.edges[source.ID][targetKey]; !ok {
+		graph.edges[source.ID][targetKey] = new(big.Rat)
+	}
+	graph.edges[source.ID][targetKey].Add(graph.edges[source.ID][targetKey], abs)
+
+	graph.cancelOpposites(sourceNode, target.ID, nodeType)
+}
+
+// finalize sets the degrees and adds the links to the negConsumer
+// unlike rep, nodes with a negative rank of 0 don't get a (0 weight) link, like in detrep
+func (graph *Graph) finalize() {
+	// the degree is the sum of the links that are left after cancelling, it is not tracked
+	// while linking so a mistake in the bookkeeping of rep and detrep shows up as a different rank
+	for id, edges := range graph.edges {
+		degree := graph.nodes[id].degree
+		for _, weight := range edges {
+			degree.Add(degree, weight)
+		}
+	}
+
+	for _, negNode := range graph.negNodes {
+		posNode, ok := graph.nodes[negNode.id]
+		if !ok || posNode.degree.Sign() == 0 || posNode.rank.Sign() == 0 || negNode.rank.Sign() == 0 {
+			continue
+		}
+		graph.initNode(NegConsumerID, Node{ID: NegConsumerID, PRank: graph.negConsumerRank, NRank: new(big.Rat)}, Positive)
+
+		ratio := new(big.Rat).Quo(negNode.rank, posNode.rank)
+		var negMultiple *big.Rat
+		if ratio.Cmp(maxNegRatio()) > 0 {
+			negMultiple = big.NewRat(MaxNegOffset, 1)
+		} else {
+			// 1 / (1 - ratio) - 1
+			one := big.NewRat(1, 1)
+			negMultiple = new(big.Rat).Sub(new(big.Rat).Inv(new(big.Rat).Sub(one, ratio)), one)
+		}
+		negWeight := new(big.Rat).Mul(negMultiple, posNode.degree)
+
+		if _, ok := graph.edges[negNode.id][NegConsumerID]; !ok {
+			graph.edges[negNode.id][NegConsumerID] = new(big.Rat)
+		}
+		graph.edges[negNode.id][NegConsumerID].Add(graph.edges[negNode.id][NegConsumerID], negWeight)
+		posNode.degree.Add(posNode.degree, negWeight)
+	}
+}
+
+// if there is both a positive and a negative link from A to B we cancel them out
+func (graph *Graph) cancelOpposites(sourceNode *node, target string, nodeType NodeType) {
+	key := getKey(target, nodeType)
+	oppositeKey := getKey(target, Positive)
+	if nodeType == Positive {
+		oppositeKey = getKey(target, Negative)
+	}
+
+	opposite, ok := graph.edges[sourceNode.id][oppositeKey]
+	if !ok {
+		return
+	}
+	edge := graph.edges[sourceNode.id][key]
+
+	switch edge.Cmp(opposite) {
+	case -1:
+		graph.removeEdge(sourceNode.id, key)
+		opposite.Sub(opposite, edge)
+	case 1:
+		graph.removeEdge(sourceNode.id, oppositeKey)
+		edge.Sub(edge, opposite)
+	default:
+		graph.removeEdge(sourceNode.id, oppositeKey)
+		graph.removeEdge(sourceNode.id, key)
+	}
+}
+
+// initNode initializes a node, the last cached rank wins
+func (graph *Graph) initNode(key string, input Node, nodeType NodeType) *node {
+	n, ok := graph.nodes[key]
+	if !ok {
+		n = &node{id: input.ID, degree: new(big.Rat), nodeType: nodeType}
+		graph.nodes[key] = n
+		if nodeType == Negative {
+			graph.negNodes[key] = n
+		}
+	}
+	rank := input.PRank
+	if nodeType == Negative {
+		rank = input.NRank
+	}
+	n.rank = new(big.Rat).Set(rank)
+	return n
+}
+
+// removeEdge removes edge from graph
+func (graph *Graph) removeEdge(source string, target string) {
+	delete(graph.edges[source], target)
+	if len(graph.edges[source]) == 0 {
+		delete(graph.edges, source)
+	}
+}
+
+// maxNegRatio is MaxNegOffset / (MaxNegOffset + 1)
+func maxNegRatio() *big.Rat {
+	return big.NewRat(MaxNegOffset, MaxNegOffset+1)
+}
+
+// getKey returns node id for positive nodes and <id>_1 for negative nodes, like rep and detrep
+func getKey(key string, nodeType NodeType) string {
+	if nodeType == Positive {
+		return key
+	}
+	return key + "_" + strconv.FormatInt(int64(nodeType), 10)
+}
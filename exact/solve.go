@@ -0,0 +1,177 @@
+package exact
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// ErrSingular is returned when the ranks are not unique, ex. α = 1 with several closed components
+var ErrSingular = errors.New("exact: the stationary vector is not unique")
+
+// Result holds the exact ranks of a single node
+type Result struct {
+	ID    string
+	PRank *big.Rat
+	NRank *big.Rat
+}
+
+// Rank returns the exact stationary ranks of the graph, keyed by id like the results of rep and detrep
+//
+// the ranks x are the fixed point of one iteration of Rank:
+//
+//	x = α Wᵀ x + α u (dᵀ x) + (1 - α) v (1ᵀ x)
+//
+// where W holds the normalized edge weights, d marks the dangling nodes and u = v are
+// the normalized personalization weights, or 1/N for every node without personalization.
+// we solve (I - M) x = 0 with the first equation replaced by 1ᵀ x = 1
+//
+// the graph is finalized, it should not be linked or ranked again
+func (graph *Graph) Rank() (map[string]Result, error) {
+	graph.finalize()
+
+	keys := make([]string, 0, len(graph.nodes))
+	for key := range graph.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	n := len(keys)
+	index := make(map[string]int, n)
+	for i, key := range keys {
+		index[key] = i
+	}
+
+	results := map[string]Result{}
+	if n == 0 {
+		return results, nil
+	}
+
+	// matrix[row][col] of I - M, with an extra column for the right hand side
+	matrix := make([][]*big.Rat, n)
+	for i := range matrix {
+		matrix[i] = make([]*big.Rat, n+1)
+		for j := range matrix[i] {
+			matrix[i][j] = new(big.Rat)
+		}
+		matrix[i][i].SetInt64(1)
+	}
+	sub := func(row, col int, x *big.Rat) {
+		matrix[row][col].Sub(matrix[row][col], x)
+	}
+
+	α := graph.α
+	teleport := graph.teleportWeights(keys, index)
+	oneMinusα := new(big.Rat).Sub(big.NewRat(1, 1), α)
+	for col, key := range keys {
+		source := graph.nodes[key]
+		if source.degree.Sign() == 0 {
+			// dangling rank is redistributed like the random jumps
+			for row, u := range teleport {
+				sub(row, col, new(big.Rat).Mul(α, u))
+			}
+		} else {
+			for target, weight := range graph.edges[key] {
+				w := new(big.Rat).Quo(weight, source.degree)
+				sub(index[target], col, w.Mul(w, α))
+			}
+		}
+		for row, v := range teleport {
+			sub(row, col, new(big.Rat).Mul(oneMinusα, v))
+		}
+	}
+
+	// the ranks add up to 1
+	for col := range matrix[0] {
+		matrix[0][col].SetInt64(1)
+	}
+
+	x, err := solve(matrix)
+	if err != nil {
+		return nil, err
+	}
+
+	// merge negative nodes like processResults
+	for i, key := range keys {
+		node := graph.nodes[key]
+		result, ok := results[node.id]
+		if !ok {
+			result = Result{ID: node.id, PRank: new(big.Rat), NRank: new(big.Rat)}
+		}
+		if node.nodeType == Negative {
+			result.NRank = x[i]
+		} else {
+			result.PRank = x[i]
+		}
+		results[node.id] = result
+	}
+	return results, nil
+}
+
+// teleportWeights returns the share of the random jumps that goes to each node
+func (graph *Graph) teleportWeights(keys []string, index map[string]int) []*big.Rat {
+	weights := make([]*big.Rat, len(keys))
+	for i := range weights {
+		weights[i] = new(big.Rat)
+	}
+	if len(graph.personalization) == 0 {
+		for i := range weights {
+			weights[i].SetFrac64(1, int64(len(keys)))
+		}
+		return weights
+	}
+
+	// personalization nodes are weighted by degree, 1 if they have no outgoing links
+	degrees := make([]*big.Rat, len(graph.personalization))
+	sum := new(big.Rat)
+	for i, key := range graph.personalization {
+		degrees[i] = big.NewRat(1, 1)
+		if degree := graph.nodes[key].degree; degree.Sign() > 0 {
+			degrees[i] = degree
+		}
+		sum.Add(sum, degrees[i])
+	}
+	for i, key := range graph.personalization {
+		w := weights[index[key]]
+		w.Add(w, new(big.Rat).Quo(degrees[i], sum))
+	}
+	return weights
+}
+
+// solve solves an augmented n x (n+1) system with Gauss-Jordan elimination
+func solve(matrix [][]*big.Rat) ([]*big.Rat, error) {
+	n := len(matrix)
+	for col := 0; col < n; col++ {
+		pivot := -1
+		for row := col; row < n; row++ {
+			if matrix[row][col].Sign() != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot < 0 {
+			return nil, ErrSingular
+		}
+		matrix[col], matrix[pivot] = matrix[pivot], matrix[col]
+
+		inv := new(big.Rat).Inv(matrix[col][col])
+		for j := col; j <= n; j++ {
+			matrix[col][j].Mul(matrix[col][j], inv)
+		}
+		for row := 0; row < n; row++ {
+			factor := matrix[row][col]
+			if row == col || factor.Sign() == 0 {
+				continue
+			}
+			factor = new(big.Rat).Set(factor)
+			for j := col; j <= n; j++ {
+				matrix[row][j].Sub(matrix[row][j], new(big.Rat).Mul(factor, matrix[col][j]))
+			}
+		}
+	}
+
+	x := make([]*big.Rat, n)
+	for i := range x {
+		x[i] = matrix[i][n]
+	}
+	return x, nil
+}
@@ -0,0 +1,134 @@
+package exact
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func rat(a, b int64) *big.Rat { return big.NewRat(a, b) }
+
+func input(id string) Node { return NewNode(id, nil, nil) }
+
+func expectRanks(t *testing.T, results map[string]Result, expected map[string][2]*big.Rat) {
+	t.Helper()
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for id, e := range expected {
+		r, ok := results[id]
+		if !ok || r.PRank.Cmp(e[0]) != 0 || r.NRank.Cmp(e[1]) != 0 {
+			t.Errorf("%s expected %s %s got %v", id, e[0], e[1], r)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	results, err := NewGraph(rat(85, 100), rat(0, 1)).Rank()
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected no results, got %v %v", results, err)
+	}
+}
+
+func TestCircle(t *testing.T) {
+	graph := NewGraph(rat(85, 100), rat(0, 1))
+	graph.Link(input("a"), input("b"), rat(1, 1))
+	graph.Link(input("b"), input("c"), rat(1, 1))
+	graph.Link(input("c"), input("d"), rat(1, 1))
+	graph.Link(input("d"), input("a"), rat(1, 1))
+
+	results, err := graph.Rank()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := rat(0, 1)
+	expectRanks(t, results, map[string][2]*big.Rat{
+		"a": {rat(1, 4), zero}, "b": {rat(1, 4), zero}, "c": {rat(1, 4), zero}, "d": {rat(1, 4), zero},
+	})
+}
+
+func TestPersonalizedDangling(t *testing.T) {
+	// b = α a, a = (1 - α) + α b (the dangling rank of b goes back to a)
+	graph := NewGraph(rat(17, 20), rat(0, 1))
+	graph.AddPersonalizationNode(input("a"))
+	graph.Link(input("a"), input("b"), rat(1, 1))
+
+	results, err := graph.Rank()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRanks(t, results, map[string][2]*big.Rat{
+		"a": {rat(20, 37), rat(0, 1)},
+		"b": {rat(17, 37), rat(0, 1)},
+	})
+}
+
+func TestNegatives(t *testing.T) {
+	// with α = 1/2 and a personalized, b has cached ranks with a neg / pos ratio of 1/3
+	// so half of its outgoing weight goes to the negConsumer: 1 / (1 - 1/3) - 1 = 1/2
+	graph := NewGraph(rat(1, 2), rat(0, 1))
+	a := input("a")
+	b := NewNode("b", rat(3, 10), rat(1, 10))
+	c := input("c")
+	graph.AddPersonalizationNode(a)
+	graph.Link(a, b, rat(2, 1))
+	graph.Link(a, c, rat(2, 1))
+	graph.Link(c, b, rat(-1, 1))
+	graph.Link(b, c, rat(1, 1))
+	// cancelled by the positive link
+	graph.Link(a, c, rat(-1, 1))
+
+	results, err := graph.Rank()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a: 2 to b, 1 to c; b: 1 to c, 1/2 to negConsumer; c: 1 to b_1
+	// every dangling node (b_1, negConsumer) sends its rank back to a
+	// a = 1/2 + 1/2 (b_1 + n), b = 1/3 a, c = 1/6 a + 1/3 b, b_1 = 1/2 c, n = 1/6 b
+	// => a = 1/2 + 7/72 a = 36/65
+	x := map[string]*big.Rat{"a": rat(36, 65)}
+	x["b"] = new(big.Rat).Mul(rat(1, 3), x["a"])
+	x["c"] = new(big.Rat).Add(new(big.Rat).Mul(rat(1, 6), x["a"]), new(big.Rat).Mul(rat(1, 3), x["b"]))
+	x["b_1"] = new(big.Rat).Mul(rat(1, 2), x["c"])
+	x["n"] = new(big.Rat).Mul(rat(1, 6), x["b"])
+
+	zero := rat(0, 1)
+	expectRanks(t, results, map[string][2]*big.Rat{
+		"a":           {x["a"], zero},
+		"b":           {x["b"], x["b_1"]},
+		"c":           {x["c"], zero},
+		NegConsumerID: {x["n"], zero},
+	})
+
+	sum := new(big.Rat)
+	for _, r := range results {
+		sum.Add(sum, r.PRank).Add(sum, r.NRank)
+	}
+	if sum.Cmp(rat(1, 1)) != 0 {
+		t.Errorf("ranks should add up to 1, got %s", sum)
+	}
+}
+
+func TestMaxNegOffset(t *testing.T) {
+	// links from nodes with a neg / pos ratio above 10/11 are ignored
+	graph := NewGraph(rat(85, 100), rat(0, 1))
+	graph.Link(NewNode("a", rat(1, 10), rat(1, 10)), input("b"), rat(1, 1))
+	results, err := graph.Rank()
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected no results, got %v %v", results, err)
+	}
+}
+
+func TestSingular(t *testing.T) {
+	// two closed loops without random jumps have many stationary vectors
+	graph := NewGraph(rat(1, 1), rat(0, 1))
+	graph.Link(input("a"), input("b"), rat(1, 1))
+	graph.Link(input("b"), input("a"), rat(1, 1))
+	graph.Link(input("c"), input("d"), rat(1, 1))
+	graph.Link(input("d"), input("c"), rat(1, 1))
+
+	if _, err := graph.Rank(); !errors.Is(err, ErrSingular) {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+}
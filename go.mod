@@ -4,8 +4,19 @@ go 1.15
 
 require (
 	github.com/cosmos/cosmos-sdk v0.42.3
-	github.com/google/go-cmp v0.5.0
 	github.com/go-echarts/go-echarts/v2 v2.2.4
+	github.com/gogo/protobuf v1.3.3
+	github.com/golang/protobuf v1.4.3
+	github.com/google/go-cmp v0.5.0
+	github.com/gorilla/mux v1.8.0
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0
+	github.com/spf13/cobra v1.1.1
+	github.com/tendermint/tendermint v0.34.8
+	github.com/tendermint/tm-db v0.6.4
+	google.golang.org/genproto v0.0.0-20210114201628-6edceaf6022f
+	google.golang.org/grpc v1.35.0
 )
 
 replace github.com/gogo/protobuf => github.com/regen-network/protobuf v1.3.3-alpha.regen.1
+
+replace google.golang.org/grpc => google.golang.org/grpc v1.33.2
@@ -0,0 +1,79 @@
+// Package ranktest has test helpers shared by the ranking engines that take the same inputs as rep.Graph
+package ranktest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/relevant-community/reputation/rep"
+)
+
+// Graph is a ranking engine with the inputs and the Rank callback of rep.Graph
+type Graph interface {
+	rep.Linker
+	Rank(callback func(id string, pRank float64, nRank float64))
+}
+
+// Link is a signed link between two nodes
+type Link struct {
+	Source, Target string
+	Weight         float64
+}
+
+// PositiveLinks is a small graph without negative links, the links from b to e cancel out
+var PositiveLinks = []Link{
+	{"a", "b", 1}, {"a", "c", 2}, {"b", "c", 1}, {"c", "d", 1}, {"d", "b", 3}, {"d", "e", 1}, {"f", "c", 1},
+	{"b", "e", 1}, {"b", "e", -1},
+}
+
+// Node returns a node without cached ranks
+func Node(id string) rep.Node { return rep.NewNode(id, 0, 0) }
+
+// Rank ranks graph and collects the results by id
+func Rank(graph Graph) map[string]rep.Ranks {
+	results := map[string]rep.Ranks{}
+	graph.Rank(func(id string, pRank float64, nRank float64) {
+		results[id] = rep.Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	return results
+}
+
+// ExpectRanks checks that results has the expected positive and negative ranks of every node and no other nodes
+func ExpectRanks(t *testing.T, results map[string]rep.Ranks, expected map[string][2]float64) {
+	t.Helper()
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %v", len(expected), results)
+	}
+	for id, e := range expected {
+		if r := results[id]; math.Abs(r.PRank-e[0]) > 1e-9 || math.Abs(r.NRank-e[1]) > 1e-9 {
+			t.Errorf("%s expected %v but got %v", id, e, r)
+		}
+	}
+}
+
+// ExpectEmpty checks that an empty graph has no results
+func ExpectEmpty(t *testing.T, graph Graph) {
+	t.Helper()
+	if results := Rank(graph); len(results) != 0 {
+		t.Errorf("expected no results, got %v", results)
+	}
+}
+
+// ExpectRepRanks links both graphs with seeds and links and checks that graph ranks like repGraph
+func ExpectRepRanks(t *testing.T, graph Graph, repGraph *rep.Graph, seeds []string, links []Link) {
+	t.Helper()
+	for _, seed := range seeds {
+		graph.AddPersonalizationNode(Node(seed))
+		repGraph.AddPersonalizationNode(Node(seed))
+	}
+	for _, link := range links {
+		graph.Link(Node(link.Source), Node(link.Target), link.Weight)
+		repGraph.Link(Node(link.Source), Node(link.Target), link.Weight)
+	}
+
+	expected := map[string][2]float64{}
+	repGraph.Rank(func(id string, pRank float64, nRank float64) {
+		expected[id] = [2]float64{pRank, nRank}
+	})
+	ExpectRanks(t, Rank(graph), expected)
+}
@@ -0,0 +1,204 @@
+// Package metrics is a minimal in-process metrics registry
+// metrics are exposed in the prometheus text format so they can be scraped by most dashboards
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// metric types used in the text exposition
+const (
+	counterType = "counter"
+	gaugeType   = "gauge"
+)
+
+// value is a float64 that can be updated concurrently
+type value struct {
+	mu sync.Mutex
+	v  float64
+}
+
+func (v *value) add(delta float64) {
+	v.mu.Lock()
+	v.v += delta
+	v.mu.Unlock()
+}
+
+func (v *value) set(n float64) {
+	v.mu.Lock()
+	v.v = n
+	v.mu.Unlock()
+}
+
+func (v *value) get() float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.v
+}
+
+// Counter is a value that only goes up
+type Counter struct {
+	v *value
+}
+
+// Inc increments the counter by 1
+func (c Counter) Inc() {
+	c.v.add(1)
+}
+
+// Add increments the counter, negative values are ignored
+func (c Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.v.add(delta)
+}
+
+// Value returns the current value of the counter
+func (c Counter) Value() float64 {
+	return c.v.get()
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	v *value
+}
+
+// Set sets the gauge
+func (g Gauge) Set(n float64) {
+	g.v.set(n)
+}
+
+// Add adds delta to the gauge
+func (g Gauge) Add(delta float64) {
+	g.v.add(delta)
+}
+
+// Value returns the current value of the gauge
+func (g Gauge) Value() float64 {
+	return g.v.get()
+}
+
+// metric is a registered counter or gauge
+type metric struct {
+	name       string
+	help       string
+	metricType string
+	value      *value
+}
+
+// Registry holds named metrics
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]*metric
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*metric)}
+}
+
+// Counter returns the counter registered under name, creating it if it doesn't exist
+// it panics if name is already registered as a gauge
+func (registry *Registry) Counter(name, help string) Counter {
+	return Counter{v: registry.register(name, help, counterType)}
+}
+
+// Gauge returns the gauge registered under name, creating it if it doesn't exist
+// it panics if name is already registered as a counter
+func (registry *Registry) Gauge(name, help string) Gauge {
+	return Gauge{v: registry.register(name, help, gaugeType)}
+}
+
+func (registry *Registry) register(name, help, metricType string) *value {
+	if !validName(name) {
+		panic(fmt.Sprintf("metrics: invalid metric name %q", name))
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if m, ok := registry.metrics[name]; ok {
+		if m.metricType != metricType {
+			panic(fmt.Sprintf("metrics: %s is already registered as a %s", name, m.metricType))
+		}
+		return m.value
+	}
+	m := &metric{name: name, help: help, metricType: metricType, value: &value{}}
+	registry.metrics[name] = m
+	return m.value
+}
+
+// WriteText writes all metrics sorted by name in the prometheus text exposition format
+func (registry *Registry) WriteText(w io.Writer) error {
+	registry.mu.Lock()
+	metrics := make([]*metric, 0, len(registry.metrics))
+	for _, m := range registry.metrics {
+		metrics = append(metrics, m)
+	}
+	registry.mu.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name < metrics[j].name })
+
+	var b strings.Builder
+	for _, m := range metrics {
+		if m.help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", m.name, m.metricType)
+		fmt.Fprintf(&b, "%s %s\n", m.name, formatValue(m.value.get()))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler returns an http handler that serves the text exposition
+func (registry *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := registry.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// validName checks the name matches [a-zA-Z_:][a-zA-Z0-9_:]*
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || r == ':':
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func escapeHelp(help string) string {
+	help = strings.ReplaceAll(help, `\`, `\\`)
+	return strings.ReplaceAll(help, "\n", `\n`)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/relevant-community/reputation/detrep"
+	"github.com/relevant-community/reputation/rep"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.Counter("test_total", "A test counter.")
+	counter.Inc()
+	counter.Add(2)
+	counter.Add(-1)
+
+	gauge := registry.Gauge("test_gauge", "")
+	gauge.Set(1.5)
+	gauge.Add(-0.5)
+
+	// registering the same name returns the same metric
+	registry.Counter("test_total", "A test counter.").Inc()
+
+	if counter.Value() != 4 {
+		t.Errorf("expected counter to be 4 but got %f", counter.Value())
+	}
+	if gauge.Value() != 1 {
+		t.Errorf("expected gauge to be 1 but got %f", gauge.Value())
+	}
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	expected := "# TYPE test_gauge gauge\n" +
+		"test_gauge 1\n" +
+		"# HELP test_total A test counter.\n" +
+		"# TYPE test_total counter\n" +
+		"test_total 4\n"
+
+	if rec.Body.String() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, rec.Body.String())
+	}
+}
+
+func TestRegistryTypeMismatch(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("test", "")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering a gauge with a counter name to panic")
+		}
+	}()
+	registry.Gauge("test", "")
+}
+
+func TestRepObserver(t *testing.T) {
+	registry := NewRegistry()
+	graph := rep.NewGraph(0.85, 0.000001, 0)
+	graph.Observer = NewRepObserver(registry, "rep")
+
+	a := rep.NewNode("a", 0, 0)
+	b := rep.NewNode("b", 0, 0)
+	graph.AddPersonalizationNode(a)
+	graph.Link(a, b, 1.0)
+	graph.Link(b, a, 1.0)
+
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+
+	if v := registry.Counter("rep_rank_runs_total", "").Value(); v != 1 {
+		t.Errorf("expected 1 run but got %f", v)
+	}
+	if v := registry.Counter("rep_rank_completed_total", "").Value(); v != 1 {
+		t.Errorf("expected 1 completed run but got %f", v)
+	}
+	if v := registry.Gauge("rep_rank_last_iterations", "").Value(); v == 0 {
+		t.Errorf("expected iterations to be recorded")
+	}
+	if v := registry.Gauge("rep_rank_delta", "").Value(); v > 0.000001 {
+		t.Errorf("last Δ %f should be below ε", v)
+	}
+}
+
+func TestDetrepObserver(t *testing.T) {
+	registry := NewRegistry()
+	graph := detrep.NewGraphHelper(0.85, 0.000001, detrep.FtoBD(0))
+	graph.Observer = NewDetrepObserver(registry, "detrep")
+
+	a := detrep.NewNodeInputHelper("a", 0, 0)
+	b := detrep.NewNodeInputHelper("b", 0, 0)
+	c := detrep.NewNodeInputHelper("c", 0, 0)
+	graph.AddPersonalizationNode(a)
+	graph.LinkHelper(a, b, 1.0)
+	graph.LinkHelper(a, c, -1.0)
+
+	graph.Rank(func(id string, pRank, nRank detrep.Uint) {})
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "detrep_rank_completed_total 1\n") {
+		t.Errorf("expected a completed run\n%s", out.String())
+	}
+	if v := registry.Gauge("detrep_rank_dangling_weight", "").Value(); v <= 0 || v > 1 {
+		t.Errorf("expected dangling weight between 0 and 1 but got %f", v)
+	}
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/relevant-community/reputation/detrep"
+	"github.com/relevant-community/reputation/rep"
+)
+
+// rankMetrics are the metrics recorded by the rank observers
+type rankMetrics struct {
+	runs           Counter
+	completed      Counter
+	iterations     Counter
+	nodes          Gauge
+	lastIterations Gauge
+	delta          Gauge
+	danglingWeight Gauge
+	negConsumer    Gauge
+	duration       Gauge
+
+	mu      sync.Mutex
+	started time.Time
+}
+
+// newRankMetrics registers the rank metrics, all names start with prefix
+func newRankMetrics(registry *Registry, prefix string) *rankMetrics {
+	return &rankMetrics{
+		runs:           registry.Counter(prefix+"_rank_runs_total", "Number of started rank computations."),
+		completed:      registry.Counter(prefix+"_rank_completed_total", "Number of rank computations that converged."),
+		iterations:     registry.Counter(prefix+"_rank_iterations_total", "Number of power iterations over all rank computations."),
+		nodes:          registry.Gauge(prefix+"_rank_nodes", "Number of nodes in the graph being ranked, including negative nodes."),
+		lastIterations: registry.Gauge(prefix+"_rank_last_iterations", "Number of iterations of the last rank computation."),
+		delta:          registry.Gauge(prefix+"_rank_delta", "Global error between the last two iterations."),
+		danglingWeight: registry.Gauge(prefix+"_rank_dangling_weight", "Rank redistributed from nodes with no outgoing links in the last iteration."),
+		negConsumer:    registry.Gauge(prefix+"_rank_neg_consumer", "Rank of the negConsumer node."),
+		duration:       registry.Gauge(prefix+"_rank_duration_seconds", "Duration of the last rank computation."),
+	}
+}
+
+func (m *rankMetrics) start(nodes int) {
+	m.mu.Lock()
+	m.started = time.Now()
+	m.mu.Unlock()
+
+	m.runs.Inc()
+	m.nodes.Set(float64(nodes))
+	m.lastIterations.Set(0)
+}
+
+func (m *rankMetrics) iteration(iteration int, delta, danglingWeight, negConsumer float64) {
+	m.iterations.Inc()
+	m.lastIterations.Set(float64(iteration))
+	m.delta.Set(delta)
+	m.danglingWeight.Set(danglingWeight)
+	m.negConsumer.Set(negConsumer)
+}
+
+func (m *rankMetrics) complete() {
+	m.mu.Lock()
+	elapsed := time.Since(m.started)
+	m.mu.Unlock()
+
+	m.completed.Inc()
+	m.duration.Set(elapsed.Seconds())
+}
+
+// RepObserver is a rep.Observer that records convergence metrics
+type RepObserver struct {
+	metrics *rankMetrics
+}
+
+var _ rep.Observer = (*RepObserver)(nil)
+
+// NewRepObserver registers rank metrics with the given name prefix
+func NewRepObserver(registry *Registry, prefix string) *RepObserver {
+	return &RepObserver{metrics: newRankMetrics(registry, prefix)}
+}
+
+// Start implements rep.Observer
+func (o *RepObserver) Start(graph *rep.Graph) {
+	o.metrics.start(len(graph.Nodes))
+}
+
+// Iteration implements rep.Observer
+func (o *RepObserver) Iteration(graph *rep.Graph, stats rep.IterationStats) {
+	o.metrics.iteration(stats.Iteration, stats.Delta, stats.DanglingWeight, stats.NegConsumerRank)
+}
+
+// Complete implements rep.Observer
+func (o *RepObserver) Complete(graph *rep.Graph, stats rep.IterationStats) {
+	o.metrics.complete()
+}
+
+// DetrepObserver is a detrep.Observer that records convergence metrics
+// values are converted to floats using the graph precision
+type DetrepObserver struct {
+	metrics *rankMetrics
+}
+
+var _ detrep.Observer = (*DetrepObserver)(nil)
+
+// NewDetrepObserver registers rank metrics with the given name prefix
+func NewDetrepObserver(registry *Registry, prefix string) *DetrepObserver {
+	return &DetrepObserver{metrics: newRankMetrics(registry, prefix)}
+}
+
+// Start implements detrep.Observer
+func (o *DetrepObserver) Start(graph *detrep.Graph) {
+	o.metrics.start(len(graph.Nodes))
+}
+
+// Iteration implements detrep.Observer
+func (o *DetrepObserver) Iteration(graph *detrep.Graph, stats detrep.IterationStats) {
+	o.metrics.iteration(
+		stats.Iteration,
+		toFloat(stats.Delta, graph.Precision),
+		toFloat(stats.DanglingWeight, graph.Precision),
+		toFloat(stats.NegConsumerRank, graph.Precision),
+	)
+}
+
+// Complete implements detrep.Observer
+func (o *DetrepObserver) Complete(graph *detrep.Graph, stats detrep.IterationStats) {
+	o.metrics.complete()
+}
+
+// toFloat converts a fixed point value to a float
+func toFloat(n detrep.Uint, precision detrep.Uint) float64 {
+	f, _ := new(big.Rat).SetFrac(n.BigInt(), precision.BigInt()).Float64()
+	return f
+}
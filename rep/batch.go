@@ -0,0 +1,127 @@
+package rep
+
+import (
+	"fmt"
+	"math"
+)
+
+// Community is a named personalization vector
+type Community struct {
+	Name            string
+	Personalization []string // ids of the trusted nodes, empty for non-personalized ranks
+}
+
+// RankCommunities ranks the graph once for every community and returns the results by community name
+// the graph is finalized and normalized once, then every iteration updates the ranks of all communities
+// in a single pass over the edges. results are the same as building the graph with the community's
+// personalization vector and calling Rank with PowerIteration, the personalization of the graph is ignored
+//
+// all communities share the cached ranks of the graph, including the ones used for the negConsumer links
+// it must be called before Rank, the graph is not modified
+func (graph *Graph) RankCommunities(communities []Community) (map[string]map[string]Ranks, error) {
+	if graph.rankState != nil {
+		return nil, ErrRanked
+	}
+	names := map[string]bool{}
+	for _, community := range communities {
+		if names[community.Name] {
+			return nil, fmt.Errorf("community %s is listed more than once", community.Name)
+		}
+		names[community.Name] = true
+		for _, id := range community.Personalization {
+			if node, ok := graph.Nodes[id]; !ok || node.nodeType != Positive {
+				return nil, fmt.Errorf("community %s: node %s not found", community.Name, id)
+			}
+		}
+	}
+
+	frozen := graph.Clone()
+	frozen.Finalize()
+	pWeights := make([][]float64, len(communities))
+	for k, community := range communities {
+		pWeights[k] = frozen.personalizationWeights(community.Personalization)
+	}
+	frozen.normalizeEdges()
+
+	system := frozen.newLinearSystem()
+	v := make([][]float64, len(communities))
+	x := make([][]float64, len(communities))
+	for k, community := range communities {
+		v[k] = system.teleport(community.Personalization, pWeights[k])
+		x[k] = append([]float64{}, v[k]...)
+	}
+	system.batchIterate(v, x, graph.Params.ε)
+
+	results := make(map[string]map[string]Ranks, len(communities))
+	for k, community := range communities {
+		results[community.Name] = frozen.batchResults(system.keys, x[k])
+	}
+	return results, nil
+}
+
+// batchIterate runs power iterations on every rank vector x[k] with the teleport vector v[k]
+// until all of them change by less than ε
+func (system *linearSystem) batchIterate(v, x [][]float64, ε float64) {
+	α := system.α
+	next := make([][]float64, len(x))
+	for k := range next {
+		next[k] = make([]float64, len(system.keys))
+	}
+	dangling := make([]float64, len(x))
+	converged := make([]bool, len(x))
+
+	for remaining := len(x); remaining > 0; {
+		for k := range x {
+			if !converged[k] {
+				dangling[k] = system.danglingRank(x[k])
+			}
+		}
+
+		// a single pass over the edges for all communities
+		for i, edges := range system.in {
+			for k := range x {
+				if converged[k] {
+					continue
+				}
+				rank := system.self[i]*x[k][i] + (α*dangling[k]+1-α)*v[k][i]
+				for _, edge := range edges {
+					rank += edge.weight * x[k][edge.source]
+				}
+				next[k][i] = rank
+			}
+		}
+
+		for k := range x {
+			if converged[k] {
+				continue
+			}
+			normalize(next[k])
+			var Δ float64
+			for i := range x[k] {
+				Δ += math.Abs(next[k][i] - x[k][i])
+			}
+			x[k], next[k] = next[k], x[k]
+			if Δ <= ε {
+				converged[k] = true
+				remaining--
+			}
+		}
+	}
+}
+
+// batchResults merges the ranks of negative nodes into their positive nodes like processResults
+func (graph *Graph) batchResults(keys []string, x []float64) map[string]Ranks {
+	results := make(map[string]Ranks, len(keys))
+	for i, key := range keys {
+		node := graph.Nodes[key]
+		result := results[node.ID]
+		result.ID = node.ID
+		if node.nodeType == Negative {
+			result.NRank = x[i]
+		} else {
+			result.PRank = x[i]
+		}
+		results[node.ID] = result
+	}
+	return results
+}
@@ -0,0 +1,73 @@
+package rep
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestRankCommunities(t *testing.T) {
+	_, cached := rankTwice(whatIfLinks)
+	communities := []Community{
+		{Name: "a", Personalization: []string{"a"}},
+		{Name: "bc", Personalization: []string{"b", "c"}},
+		{Name: "all"},
+	}
+
+	graph := unrankedGraph(cached, whatIfLinks)
+	hash := mustHash(t, graph)
+	batch, err := graph.RankCommunities(communities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hash, mustHash(t, graph)) {
+		t.Error("RankCommunities should not modify the graph")
+	}
+
+	for _, community := range communities {
+		// rank a graph built with the community's personalization
+		expected := map[string]Ranks{}
+		single := NewGraph(0.85, 0.000001, cached[NegConsumerID].PRank)
+		nodes := map[string]Node{}
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			nodes[id] = NewNode(id, cached[id].PRank, cached[id].NRank)
+		}
+		for _, id := range community.Personalization {
+			single.AddPersonalizationNode(nodes[id])
+		}
+		whatIfLinks(single, nodes)
+		single.Rank(func(id string, pRank float64, nRank float64) {
+			expected[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+		})
+
+		results := batch[community.Name]
+		if len(results) != len(expected) {
+			t.Fatalf("%s: expected %d results, got %d", community.Name, len(expected), len(results))
+		}
+		for id, e := range expected {
+			r := results[id]
+			if math.Abs(r.PRank-e.PRank) > 2e-5 || math.Abs(r.NRank-e.NRank) > 2e-5 {
+				t.Errorf("%s: %s expected %v but got %v", community.Name, id, e, r)
+			}
+		}
+	}
+
+	if batch["a"]["a"].PRank <= batch["bc"]["a"].PRank {
+		t.Errorf("a should rank higher in its own community %v %v", batch["a"]["a"], batch["bc"]["a"])
+	}
+}
+
+func TestRankCommunitiesErrors(t *testing.T) {
+	graph := unrankedGraph(nil, whatIfLinks)
+	if _, err := graph.RankCommunities([]Community{{Name: "x", Personalization: []string{"x"}}}); err == nil {
+		t.Error("expected an error for an unknown node")
+	}
+	if _, err := graph.RankCommunities([]Community{{Name: "a"}, {Name: "a"}}); err == nil {
+		t.Error("expected an error for a duplicate community")
+	}
+
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+	if _, err := graph.RankCommunities([]Community{{Name: "a"}}); err != ErrRanked {
+		t.Errorf("expected ErrRanked, got %v", err)
+	}
+}
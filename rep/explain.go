@@ -0,0 +1,127 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNotRanked is returned by queries that need the results of Rank
+var ErrNotRanked = errors.New("graph has not been ranked")
+
+// Contribution is the rank a node receives through a link from one of its in-neighbours
+type Contribution struct {
+	Source string  // id of the in-neighbour
+	Weight float64 // normalized weight of the link
+	Rank   float64 // α · rank of the source · weight
+}
+
+// Breakdown splits the rank of a positive or negative node into its sources
+type Breakdown struct {
+	Rank          float64
+	Contributions []Contribution // sorted by rank, highest first
+	Teleport      float64        // rank from random jumps and dangling nodes
+	Residual      float64        // rank not accounted for by contributions and teleport (convergence error)
+}
+
+// Explanation describes where the ranks of a node come from
+type Explanation struct {
+	ID       string
+	Positive Breakdown // PRank, from positive links
+	Negative Breakdown // NRank, from negative links
+	// NegConsumerWeight is the share of the node's outgoing weight taken by the negConsumer link
+	// 0 if the node had no negative rank in the previous computation
+	NegConsumerWeight float64
+	// NegConsumerRank is the rank the node sent to the negConsumer instead of the nodes it links to
+	NegConsumerRank float64
+}
+
+// Explain decomposes the ranks of a node into contributions from its in-neighbours and teleport
+// it must be called after Rank
+// contributions are computed from the converged ranks, so they add up to the node's rank within ε
+func (graph *Graph) Explain(id string) (Explanation, error) {
+	state := graph.rankState
+	if state == nil {
+		return Explanation{}, ErrNotRanked
+	}
+
+	posNode, isPos := graph.Nodes[id]
+	negNode, isNeg := graph.NegNodes[getKey(id, Negative)]
+	if !isPos && !isNeg {
+		return Explanation{}, fmt.Errorf("node %s not found", id)
+	}
+
+	explanation := Explanation{ID: id}
+	if isPos {
+		explanation.Positive = graph.breakdown(id, posNode.PRank, graph.teleport(id, Positive))
+	}
+	if isNeg {
+		explanation.Negative = graph.breakdown(getKey(id, Negative), negNode.PRank, graph.teleport(id, Negative))
+	}
+
+	if isPos {
+		if weight, ok := graph.Edges[id][graph.NegConsumer.ID]; ok {
+			explanation.NegConsumerWeight = weight
+			explanation.NegConsumerRank = graph.Params.α * posNode.PRank * weight
+		}
+	}
+
+	return explanation, nil
+}
+
+// breakdown collects all the links pointing to key
+func (graph *Graph) breakdown(key string, rank float64, teleport float64) Breakdown {
+	α := graph.Params.α
+	result := Breakdown{Rank: rank, Teleport: teleport, Contributions: []Contribution{}}
+
+	total := teleport
+	for source, targets := range graph.Edges {
+		weight, ok := targets[key]
+		if !ok {
+			continue
+		}
+		contribution := Contribution{
+			Source: source,
+			Weight: weight,
+			Rank:   α * graph.Nodes[source].PRank * weight,
+		}
+		total += contribution.Rank
+		result.Contributions = append(result.Contributions, contribution)
+	}
+
+	sort.Slice(result.Contributions, func(i, j int) bool {
+		a, b := result.Contributions[i], result.Contributions[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		return a.Source < b.Source
+	})
+
+	result.Residual = rank - total
+	return result
+}
+
+// teleport returns the rank a node gets from random jumps and dangling nodes in the last iteration
+func (graph *Graph) teleport(id string, nodeType NodeType) float64 {
+	state := graph.rankState
+	α := graph.Params.α
+	dangling := state.stats.DanglingWeight
+
+	if len(graph.Params.Personalization) == 0 {
+		if state.n == 0 {
+			return 0
+		}
+		return (1-α)/state.n + dangling/state.n
+	}
+
+	// only positive personalization nodes get the random jumps
+	var teleport float64
+	if nodeType == Positive {
+		for i, root := range graph.Params.Personalization {
+			if root == id {
+				teleport += (1 - α + dangling) * state.pWeights[i]
+			}
+		}
+	}
+	return teleport
+}
@@ -0,0 +1,105 @@
+package rep
+
+import (
+	"math"
+	"testing"
+)
+
+func rankTwice(links func(graph *Graph, nodes map[string]Node)) (*Graph, map[string]Ranks) {
+	ids := []string{"a", "b", "c", "d", "e"}
+	results := map[string]Ranks{}
+	var graph *Graph
+	for pass := 0; pass < 2; pass++ {
+		graph = NewGraph(0.85, 0.000001, results[NegConsumerID].PRank)
+		nodes := map[string]Node{}
+		for _, id := range ids {
+			nodes[id] = NewNode(id, results[id].PRank, results[id].NRank)
+		}
+		graph.AddPersonalizationNode(nodes["a"])
+		links(graph, nodes)
+
+		results = map[string]Ranks{}
+		graph.Rank(func(id string, pRank float64, nRank float64) {
+			results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+		})
+	}
+	return graph, results
+}
+
+func checkBreakdown(t *testing.T, id string, b Breakdown) {
+	total := b.Teleport + b.Residual
+	for _, c := range b.Contributions {
+		total += c.Rank
+	}
+	if math.Abs(total-b.Rank) > 1e-12 {
+		t.Errorf("%s: contributions %f don't add up to rank %f", id, total, b.Rank)
+	}
+	if math.Abs(b.Residual) > 0.000001 {
+		t.Errorf("%s: residual %f should be within ε", id, b.Residual)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	graph, results := rankTwice(func(graph *Graph, n map[string]Node) {
+		graph.Link(n["a"], n["b"], 1.0)
+		graph.Link(n["a"], n["c"], 2.0)
+		graph.Link(n["c"], n["d"], 1.0)
+		graph.Link(n["b"], n["d"], -1.0)
+		graph.Link(n["d"], n["e"], 1.0)
+	})
+
+	for _, id := range []string{"a", "b", "c", "d", "e", NegConsumerID} {
+		explanation, err := graph.Explain(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if explanation.Positive.Rank != results[id].PRank || explanation.Negative.Rank != results[id].NRank {
+			t.Errorf("%s: explanation ranks don't match results", id)
+		}
+		checkBreakdown(t, id, explanation.Positive)
+		checkBreakdown(t, id, explanation.Negative)
+	}
+
+	a, _ := graph.Explain("a")
+	if a.Positive.Teleport == 0 || len(a.Positive.Contributions) != 0 {
+		t.Errorf("personalization node rank should only come from teleport")
+	}
+
+	d, _ := graph.Explain("d")
+	if len(d.Positive.Contributions) != 1 || d.Positive.Contributions[0].Source != "c" {
+		t.Errorf("expected d to get positive rank from c %v", d.Positive.Contributions)
+	}
+	if len(d.Negative.Contributions) != 1 || d.Negative.Contributions[0].Source != "b" {
+		t.Errorf("expected d to get negative rank from b %v", d.Negative.Contributions)
+	}
+	if d.NegConsumerWeight <= 0 || d.NegConsumerRank <= 0 {
+		t.Errorf("expected the outgoing weight of d to be reduced by the negConsumer")
+	}
+
+	e, _ := graph.Explain("e")
+	if math.Abs(e.Positive.Contributions[0].Weight-(1-d.NegConsumerWeight)) > 1e-12 {
+		t.Errorf("expected the link from d to e to carry the weight not taken by negConsumer")
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	graph := NewGraph(0.85, 0.000001, 0)
+	graph.Link(NewNode("a", 0, 0), NewNode("b", 0, 0), 1.0)
+
+	if _, err := graph.Explain("a"); err != ErrNotRanked {
+		t.Errorf("expected ErrNotRanked but got %v", err)
+	}
+
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+
+	if _, err := graph.Explain("x"); err == nil {
+		t.Errorf("expected an error for a missing node")
+	}
+
+	// non-personalized teleport is shared by all nodes
+	a, err := graph.Explain("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBreakdown(t, "a", a.Positive)
+}
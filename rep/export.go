@@ -0,0 +1,289 @@
+package rep
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// colors used by the exporters, these match the ones used in vis
+const (
+	positiveColor = "#39FF14"
+	negativeColor = "#FFC0CB"
+	neutralColor  = "#D3D3D3"
+)
+
+// exportNode is a node as it appears in an exported file
+type exportNode struct {
+	ID          string
+	Seed        bool
+	NegConsumer bool
+	Ranked      bool
+	Ranks       Ranks
+}
+
+// Rank is the net rank of the node (positive - negative)
+func (node exportNode) Rank() float64 {
+	return node.Ranks.PRank - node.Ranks.NRank
+}
+
+// exportEdge is an edge between two original ids
+// negative edges have a negative weight
+type exportEdge struct {
+	Source string
+	Target string
+	Weight float64
+}
+
+// exportData collects nodes and signed edges sorted by id
+// it works both before and after Rank is called - before, edge weights are the raw link weights
+// after, they are normalized and include links to the negConsumer
+func (graph *Graph) exportData(results map[string]Ranks) ([]exportNode, []exportEdge) {
+	ids := map[string]bool{}
+	for key, node := range graph.Nodes {
+		if node.nodeType == Positive {
+			// note: use the key since merged negative nodes don't keep the original id
+			ids[key] = true
+		}
+	}
+	for _, node := range graph.NegNodes {
+		ids[node.ID] = true
+	}
+
+	seeds := map[string]bool{}
+	for _, id := range graph.Params.Personalization {
+		seeds[id] = true
+	}
+
+	nodes := make([]exportNode, 0, len(ids))
+	for id := range ids {
+		result, ranked := results[id]
+		result.ID = id
+		nodes = append(nodes, exportNode{
+			ID:          id,
+			Seed:        seeds[id],
+			NegConsumer: id == graph.NegConsumer.ID,
+			Ranked:      ranked,
+			Ranks:       result,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	edges := []exportEdge{}
+	for source, targets := range graph.Edges {
+		for targetKey, weight := range targets {
+			edge := exportEdge{Source: source, Target: targetKey, Weight: weight}
+			if negNode, ok := graph.NegNodes[targetKey]; ok {
+				edge.Target = negNode.ID
+				edge.Weight = -weight
+			}
+			edges = append(edges, edge)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+
+	return nodes, edges
+}
+
+// maxAbsRank is used to scale node sizes
+func maxAbsRank(nodes []exportNode) float64 {
+	var max float64
+	for _, node := range nodes {
+		max = math.Max(max, math.Abs(node.Rank()))
+	}
+	return max
+}
+
+// nodeColor returns the fill color of a node based on its net rank
+func nodeColor(node exportNode) string {
+	switch {
+	case !node.Ranked || node.Rank() == 0:
+		return neutralColor
+	case node.Rank() < 0:
+		return negativeColor
+	default:
+		return positiveColor
+	}
+}
+
+// nodeScale returns a value between 0 and 1 relative to the highest ranking node
+func nodeScale(node exportNode, maxRank float64) float64 {
+	if !node.Ranked || maxRank == 0 {
+		return 0
+	}
+	return math.Abs(node.Rank()) / maxRank
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+// results are optional (can be nil), when present nodes are sized and colored by their net rank
+// personalization nodes are drawn as triangles and the negConsumer as a diamond
+// negative edges are red and dashed
+func (graph *Graph) WriteDOT(w io.Writer, results map[string]Ranks) error {
+	nodes, edges := graph.exportData(results)
+	maxRank := maxAbsRank(nodes)
+
+	var b strings.Builder
+	b.WriteString("digraph reputation {\n")
+	b.WriteString("\tnode [style=filled, fontname=\"Helvetica\"];\n")
+
+	for _, node := range nodes {
+		shape := "ellipse"
+		switch {
+		case node.NegConsumer:
+			shape = "diamond"
+		case node.Seed:
+			shape = "triangle"
+		}
+		label := node.ID
+		if node.Ranked {
+			label = fmt.Sprintf("%s\n%.4g", node.ID, node.Rank())
+		}
+		size := 0.5 + nodeScale(node, maxRank)
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=%s, fillcolor=%s, width=%g, height=%g",
+			dotQuote(node.ID), dotQuote(label), shape, dotQuote(nodeColor(node)), size, size)
+		if node.Ranked {
+			fmt.Fprintf(&b, ", pRank=%g, nRank=%g", node.Ranks.PRank, node.Ranks.NRank)
+		}
+		b.WriteString("];\n")
+	}
+
+	for _, edge := range edges {
+		attrs := fmt.Sprintf("weight=%g, label=%s", math.Abs(edge.Weight), dotQuote(fmt.Sprintf("%g", edge.Weight)))
+		if edge.Weight < 0 {
+			attrs += ", color=red, style=dashed"
+		} else {
+			attrs += ", color=darkgreen"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [%s];\n", dotQuote(edge.Source), dotQuote(edge.Target), attrs)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote returns a double-quoted DOT id
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// graphML types used for xml encoding
+type graphML struct {
+	XMLName xml.Name       `xml:"graphml"`
+	XMLNS   string         `xml:"xmlns,attr"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graph   graphMLContent `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLContent struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph in GraphML format (readable by Gephi and yEd)
+// results are optional (can be nil), when present nodes carry pRank, nRank and net rank attributes
+// along with a color and size derived from the net rank
+// edges carry a signed weight and a sign attribute
+func (graph *Graph) WriteGraphML(w io.Writer, results map[string]Ranks) error {
+	nodes, edges := graph.exportData(results)
+	maxRank := maxAbsRank(nodes)
+
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "pRank", For: "node", Name: "pRank", Type: "double"},
+			{ID: "nRank", For: "node", Name: "nRank", Type: "double"},
+			{ID: "rank", For: "node", Name: "rank", Type: "double"},
+			{ID: "seed", For: "node", Name: "seed", Type: "boolean"},
+			{ID: "negConsumer", For: "node", Name: "negConsumer", Type: "boolean"},
+			{ID: "color", For: "node", Name: "color", Type: "string"},
+			{ID: "size", For: "node", Name: "size", Type: "double"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "double"},
+			{ID: "sign", For: "edge", Name: "sign", Type: "string"},
+		},
+		Graph: graphMLContent{ID: "reputation", EdgeDefault: "directed"},
+	}
+
+	for _, node := range nodes {
+		data := []graphMLData{
+			{Key: "label", Value: node.ID},
+			{Key: "seed", Value: fmt.Sprintf("%t", node.Seed)},
+			{Key: "negConsumer", Value: fmt.Sprintf("%t", node.NegConsumer)},
+			{Key: "color", Value: nodeColor(node)},
+			{Key: "size", Value: fmt.Sprintf("%g", 10+50*nodeScale(node, maxRank))},
+		}
+		if node.Ranked {
+			data = append(data,
+				graphMLData{Key: "pRank", Value: fmt.Sprintf("%g", node.Ranks.PRank)},
+				graphMLData{Key: "nRank", Value: fmt.Sprintf("%g", node.Ranks.NRank)},
+				graphMLData{Key: "rank", Value: fmt.Sprintf("%g", node.Rank())},
+			)
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: data})
+	}
+
+	for i, edge := range edges {
+		sign := "positive"
+		if edge.Weight < 0 {
+			sign = "negative"
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: edge.Source,
+			Target: edge.Target,
+			Data: []graphMLData{
+				{Key: "weight", Value: fmt.Sprintf("%g", edge.Weight)},
+				{Key: "sign", Value: sign},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,109 @@
+package rep
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func exportTestGraph() (*Graph, map[string]Ranks) {
+	graph := NewGraph(0.85, 0.000001, 0)
+
+	a := NewNode("a", 0, 0)
+	b := NewNode("b", 0, 0)
+	c := NewNode("c", 0, 0)
+
+	graph.AddPersonalizationNode(a)
+
+	graph.Link(a, b, 2.0)
+	graph.Link(a, c, -1.0)
+
+	results := map[string]Ranks{}
+	graph.Rank(func(id string, pRank float64, nRank float64) {
+		results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	return graph, results
+}
+
+func TestWriteDOT(t *testing.T) {
+	graph, results := exportTestGraph()
+
+	var buf bytes.Buffer
+	if err := graph.WriteDOT(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"digraph reputation {",
+		`"a" [label="a\n0.5405", shape=triangle`,
+		`"c" [label="c\n-0.1532"`,
+		`"a" -> "b" [weight=0.666`,
+		`"a" -> "c" [weight=0.333`,
+		"color=red, style=dashed",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("expected DOT output to contain %q\n%s", e, out)
+		}
+	}
+}
+
+func TestWriteDOTUnranked(t *testing.T) {
+	graph := NewGraph(0.85, 0.000001, 0)
+	graph.Link(NewNode("a", 0, 0), NewNode("b", 0, 0), -3.0)
+
+	var buf bytes.Buffer
+	if err := graph.WriteDOT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"a" -> "b" [weight=3, label="-3", color=red, style=dashed];`) {
+		t.Errorf("unexpected DOT output\n%s", buf.String())
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	graph, results := exportTestGraph()
+
+	var buf bytes.Buffer
+	if err := graph.WriteGraphML(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Graph.Nodes) != 3 {
+		t.Errorf("expected 3 nodes but got %d", len(doc.Graph.Nodes))
+	}
+
+	data := func(values []graphMLData, key string) string {
+		for _, d := range values {
+			if d.Key == key {
+				return d.Value
+			}
+		}
+		return ""
+	}
+
+	for _, node := range doc.Graph.Nodes {
+		if node.ID == "a" && data(node.Data, "seed") != "true" {
+			t.Errorf("a should be marked as a seed")
+		}
+		if node.ID == "c" && data(node.Data, "color") != negativeColor {
+			t.Errorf("c should have a negative color")
+		}
+	}
+
+	for _, edge := range doc.Graph.Edges {
+		if edge.Target == "c" && data(edge.Data, "sign") != "negative" {
+			t.Errorf("edge a -> c should be negative")
+		}
+		if edge.Target == "b" && data(edge.Data, "sign") != "positive" {
+			t.Errorf("edge a -> b should be positive")
+		}
+	}
+}
@@ -23,6 +23,9 @@ import (
 // otherwise, we counter the outgoing lings with one 'heavy' link proportional to the MaxNegOffset ratio
 const MaxNegOffset = float64(10)
 
+// NegConsumerID is the id of the node that consumes the outgoing weight of nodes with a negative rank
+const NegConsumerID = "negConsumer"
+
 // NodeType is positive or negative
 // each node in the graph can be represented by two nodes,
 // a positive and a negative one
@@ -51,6 +54,22 @@ type Graph struct {
 	Edges       map[string](map[string]float64)
 	Params      RankParams
 	NegConsumer Node
+	Observer    Observer // optional, notified as Rank progresses
+	Solver      Solver   // method used by Rank, PowerIteration by default
+
+	// RetainInputs makes Rank keep an unranked copy of the graph so Simulate can be called after Rank
+	RetainInputs bool
+
+	rankState *rankState // set once Rank completes
+}
+
+// rankState holds the values used in the last iteration of Rank
+type rankState struct {
+	n        float64   // number of nodes, including negative ones
+	keys     []string  // keys of the nodes that were ranked
+	pWeights []float64 // personalization weights
+	stats    IterationStats
+	inputs   *Graph // unranked copy of the graph if RetainInputs is set, for Simulate
 }
 
 // RankParams is the pagerank parameters
@@ -73,8 +92,47 @@ func NewGraph(α, ε, negConsumerRank float64) *Graph {
 			ε:               ε, // this is the error margin used to determin convergence, usually something small
 			Personalization: make([]string, 0),
 		},
-		NegConsumer: Node{ID: "negConsumer", PRank: negConsumerRank, NRank: 0},
+		NegConsumer: Node{ID: NegConsumerID, PRank: negConsumerRank, NRank: 0},
+	}
+}
+
+// Clone returns a deep copy of the graph
+// use it to keep an unranked copy around since Rank modifies the graph
+func (graph *Graph) Clone() *Graph {
+	clone := &Graph{
+		Nodes:       make(map[string]*Node, len(graph.Nodes)),
+		NegNodes:    make(map[string]*Node, len(graph.NegNodes)),
+		Edges:       make(map[string](map[string]float64), len(graph.Edges)),
+		Params:      graph.Params,
+		NegConsumer: graph.NegConsumer,
+		Observer:    graph.Observer,
+		Solver:      graph.Solver,
+		rankState:   graph.rankState,
+
+		RetainInputs: graph.RetainInputs,
+	}
+	clone.Params.Personalization = append([]string{}, graph.Params.Personalization...)
+
+	for key, node := range graph.Nodes {
+		copied := *node
+		clone.Nodes[key] = &copied
 	}
+	for key, node := range graph.NegNodes {
+		// negative nodes are shared with the Nodes map until they are merged
+		if copied, ok := clone.Nodes[key]; ok {
+			clone.NegNodes[key] = copied
+			continue
+		}
+		copied := *node
+		clone.NegNodes[key] = &copied
+	}
+	for source, targets := range graph.Edges {
+		clone.Edges[source] = make(map[string]float64, len(targets))
+		for target, weight := range targets {
+			clone.Edges[source][target] = weight
+		}
+	}
+	return clone
 }
 
 // NewNode is ahelper method to create a node input struct
@@ -120,7 +178,7 @@ func (graph *Graph) Link(source, target Node, weight float64) {
 	graph.Edges[sourceKey][targetKey] += math.Abs(weight)
 
 	// note: use target.id here to make sure we reference the original id
-	graph.cancelOpposites(*sourceNode, target.ID, nodeType)
+	graph.cancelOpposites(sourceNode, target.ID, nodeType)
 }
 
 // Finalize is the method that runs after all other inits and before pagerank
@@ -135,42 +193,13 @@ func (graph *Graph) processNegatives() {
 	negConsumerInput := graph.NegConsumer
 
 	for _, negNode := range graph.NegNodes {
-
-		// positive node doesn't exist
-		if _, ok := graph.Nodes[negNode.ID]; ok == false {
+		negWeight, ok := graph.negWeight(negNode)
+		if !ok {
 			continue
 		}
 
-		// node has no outgpoing links
-		if graph.Nodes[negNode.ID].degree == 0 {
-			continue
-		}
-
-		posNode := graph.Nodes[negNode.ID]
-
-		if posNode.PRank == 0 {
-			continue
-		}
-
-		if negNode.PRank >= posNode.PRank {
-			panic("negative ranking nodes should not have any degree") // this should never happen
-		}
-
 		negConsumer := graph.initNode(negConsumerInput.ID, negConsumerInput, Positive)
 
-		var negMultiple float64
-
-		// this first case should not happen because we ignore these links
-		if negNode.PRank/posNode.PRank > MaxNegOffset/(MaxNegOffset+1) {
-			// cap the degree multiple at MAX_NEG_OFFSET
-			negMultiple = MaxNegOffset
-		} else {
-			negMultiple = 1/(1-negNode.PRank/posNode.PRank) - 1
-		}
-
-		// this is the weight we add to the outgoing node
-		negWeight := negMultiple * graph.Nodes[negNode.ID].degree
-
 		if _, ok := graph.Edges[negNode.ID]; ok == false {
 			graph.Edges[negNode.ID] = map[string]float64{}
 		}
@@ -180,8 +209,36 @@ func (graph *Graph) processNegatives() {
 	}
 }
 
+// negWeight returns the weight of the link from the positive counterpart of negNode to the negConsumer
+// based on the cached ranks, ok is false if the node doesn't get a negConsumer link
+func (graph *Graph) negWeight(negNode *Node) (negWeight float64, ok bool) {
+	posNode, ok := graph.Nodes[negNode.ID]
+
+	// positive node doesn't exist or has no outgoing links
+	if ok == false || posNode.degree == 0 || posNode.PRank == 0 {
+		return 0, false
+	}
+
+	if negNode.PRank >= posNode.PRank {
+		panic("negative ranking nodes should not have any degree") // this should never happen
+	}
+
+	var negMultiple float64
+
+	// this first case should not happen because we ignore these links
+	if negNode.PRank/posNode.PRank > MaxNegOffset/(MaxNegOffset+1) {
+		// cap the degree multiple at MAX_NEG_OFFSET
+		negMultiple = MaxNegOffset
+	} else {
+		negMultiple = 1/(1-negNode.PRank/posNode.PRank) - 1
+	}
+
+	// this is the weight we add to the outgoing node
+	return negMultiple * posNode.degree, true
+}
+
 // if there is both a positive and a negative link from A to B we cancel them out
-func (graph *Graph) cancelOpposites(sourceNode Node, target string, nodeType NodeType) {
+func (graph *Graph) cancelOpposites(sourceNode *Node, target string, nodeType NodeType) {
 	key := getKey(target, nodeType)
 	var oppositeKey string
 	if oppositeKey = getKey(target, Positive); nodeType == Positive {
@@ -0,0 +1,153 @@
+package rep
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash"
+	"math"
+	"sort"
+
+	"github.com/relevant-community/reputation/cache"
+)
+
+// ErrUncacheable is returned by RankCached for graphs with an Observer,
+// cached results would skip the iterations it follows
+var ErrUncacheable = errors.New("graphs with an observer can't use cached results")
+
+// hashPrefix separates rep graph hashes from detrep ones
+const hashPrefix = "rep/graph/v1"
+
+// Hash returns a canonical hash of the graph inputs:
+// params and solver, the cached rank of the negConsumer, the personalization vector,
+// the nodes with their cached ranks and the edges, in sorted order
+// links are hashed as their summed weights, so repeated or opposite links between the same
+// nodes only give the same hash if their float sums are equal, which can depend on the order they were added
+// it must be called before Rank, which modifies the graph
+func (graph *Graph) Hash() ([]byte, error) {
+	if graph.rankState != nil {
+		return nil, ErrRanked
+	}
+
+	h := sha256.New()
+	h.Write([]byte(hashPrefix))
+	writeFloat(h, graph.Params.α)
+	writeFloat(h, graph.Params.ε)
+	writeFloat(h, graph.NegConsumer.PRank)
+	// solvers give slightly different results, the default one doesn't change the hash
+	if graph.Solver != PowerIteration {
+		writeString(h, graph.Solver.String())
+	}
+
+	personalization := append([]string{}, graph.Params.Personalization...)
+	sort.Strings(personalization)
+	writeLength(h, len(personalization))
+	for _, id := range personalization {
+		writeString(h, id)
+	}
+
+	keys := sortedKeys(graph.Nodes)
+	writeLength(h, len(keys))
+	for _, key := range keys {
+		node := graph.Nodes[key]
+		writeString(h, key)
+		writeString(h, node.ID)
+		writeLength(h, int(node.nodeType))
+		writeFloat(h, node.PRank)
+	}
+
+	sources := make([]string, 0, len(graph.Edges))
+	for source := range graph.Edges {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	writeLength(h, len(sources))
+	for _, source := range sources {
+		targets := make([]string, 0, len(graph.Edges[source]))
+		for target := range graph.Edges[source] {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		writeString(h, source)
+		writeLength(h, len(targets))
+		for _, target := range targets {
+			writeString(h, target)
+			writeFloat(h, graph.Edges[source][target])
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// RankCached is Rank but returns the results cached for the graph hash if there are any
+// it returns true if the results came from the cache, in which case the graph is not ranked
+// results are passed to the callback in id order
+// graphs with an Observer return ErrUncacheable
+func (graph *Graph) RankCached(c cache.Cache, callback func(id string, pRank float64, nRank float64)) (bool, error) {
+	if graph.Observer != nil {
+		return false, ErrUncacheable
+	}
+	key, err := graph.Hash()
+	if err != nil {
+		return false, err
+	}
+
+	bz, ok, err := c.Get(key)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		var results []Ranks
+		if err := json.Unmarshal(bz, &results); err != nil {
+			return false, err
+		}
+		for _, result := range results {
+			callback(result.ID, result.PRank, result.NRank)
+		}
+		return true, nil
+	}
+
+	results := []Ranks{}
+	graph.Rank(func(id string, pRank float64, nRank float64) {
+		results = append(results, Ranks{ID: id, PRank: pRank, NRank: nRank})
+	})
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	for _, result := range results {
+		callback(result.ID, result.PRank, result.NRank)
+	}
+
+	bz, err = json.Marshal(results)
+	if err != nil {
+		return false, err
+	}
+	return false, c.Set(key, bz)
+}
+
+func sortedKeys(nodes map[string]*Node) []string {
+	keys := make([]string, 0, len(nodes))
+	for key := range nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeLength(h hash.Hash, n int) {
+	var bz [binary.MaxVarintLen64]byte
+	h.Write(bz[:binary.PutUvarint(bz[:], uint64(n))])
+}
+
+func writeString(h hash.Hash, s string) {
+	writeLength(h, len(s))
+	h.Write([]byte(s))
+}
+
+// writeFloat writes the bits of v, 0 and -0 are the same
+func writeFloat(h hash.Hash, v float64) {
+	if v == 0 {
+		v = 0
+	}
+	var bz [8]byte
+	binary.BigEndian.PutUint64(bz[:], math.Float64bits(v))
+	h.Write(bz[:])
+}
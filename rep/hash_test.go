@@ -0,0 +1,104 @@
+package rep
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/relevant-community/reputation/cache"
+)
+
+// reversedLinks adds the links of whatIfLinks in reverse order
+func reversedLinks(graph *Graph, n map[string]Node) {
+	graph.Link(n["c"], n["e"], 1.0)
+	graph.Link(n["d"], n["e"], 1.0)
+	graph.Link(n["b"], n["d"], -1.0)
+	graph.Link(n["c"], n["d"], 1.0)
+	graph.Link(n["b"], n["c"], 1.0)
+	graph.Link(n["a"], n["c"], 2.0)
+	graph.Link(n["a"], n["b"], 1.0)
+}
+
+func mustHash(t *testing.T, graph *Graph) []byte {
+	hash, err := graph.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestHash(t *testing.T) {
+	_, results := rankTwice(whatIfLinks)
+	hash := mustHash(t, unrankedGraph(results, whatIfLinks))
+
+	if !bytes.Equal(hash, mustHash(t, unrankedGraph(results, reversedLinks))) {
+		t.Error("link order should not change the hash")
+	}
+
+	changes := map[string]func(graph *Graph){
+		"weight":      func(graph *Graph) { graph.Link(NewNode("a", 0, 0), NewNode("b", 0, 0), 1) },
+		"rank":        func(graph *Graph) { graph.Nodes["c"].PRank += 0.01 },
+		"seed":        func(graph *Graph) { graph.Params.Personalization = append(graph.Params.Personalization, "b") },
+		"alpha":       func(graph *Graph) { graph.Params.α = 0.8 },
+		"negConsumer": func(graph *Graph) { graph.NegConsumer.PRank += 0.01 },
+		"solver":      func(graph *Graph) { graph.Solver = GaussSeidel },
+	}
+	for name, change := range changes {
+		graph := unrankedGraph(results, whatIfLinks)
+		change(graph)
+		if bytes.Equal(hash, mustHash(t, graph)) {
+			t.Errorf("%s: the hash should change", name)
+		}
+	}
+
+	graph := unrankedGraph(results, whatIfLinks)
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+	if _, err := graph.Hash(); err != ErrRanked {
+		t.Errorf("expected ErrRanked, got %v", err)
+	}
+}
+
+func TestRankCached(t *testing.T) {
+	_, results := rankTwice(whatIfLinks)
+	c := cache.NewMemory(0)
+
+	rank := func(graph *Graph) (map[string]Ranks, bool) {
+		ranked := map[string]Ranks{}
+		hit, err := graph.RankCached(c, func(id string, pRank float64, nRank float64) {
+			ranked[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ranked, hit
+	}
+
+	first, hit := rank(unrankedGraph(results, whatIfLinks))
+	if hit {
+		t.Error("the first computation should not come from the cache")
+	}
+
+	graph := unrankedGraph(results, reversedLinks)
+	second, hit := rank(graph)
+	if !hit {
+		t.Error("the same graph should come from the cache")
+	}
+	if graph.rankState != nil {
+		t.Error("the graph should not be ranked on a cache hit")
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("cached results differ: %v %v", first, second)
+	}
+
+	if _, hit := rank(unrankedGraph(first, whatIfLinks)); hit {
+		t.Error("new cached ranks should miss the cache")
+	}
+}
+
+func TestRankCachedObserver(t *testing.T) {
+	graph := unrankedGraph(nil, whatIfLinks)
+	graph.Observer = &testObserver{}
+	if _, err := graph.RankCached(cache.NewMemory(0), func(id string, pRank float64, nRank float64) {}); err != ErrUncacheable {
+		t.Errorf("expected ErrUncacheable, got %v", err)
+	}
+}
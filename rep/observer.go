@@ -0,0 +1,28 @@
+package rep
+
+// IterationStats describes the state of a Rank computation after an iteration
+type IterationStats struct {
+	Iteration       int     // number of completed iterations
+	Delta           float64 // global error (Δ) between the last two iterations
+	DanglingWeight  float64 // α * rank of the nodes with no outgoing links, redistributed in the last iteration
+	NegConsumerRank float64 // rank of the negConsumer node
+}
+
+// Observer can be attached to a graph to follow the progress of Rank
+// the graph passed to the observer is the one being ranked and should not be modified
+type Observer interface {
+	// Start is called once the graph is finalized and the start scores are initialized
+	Start(graph *Graph)
+	// Iteration is called after every power iteration
+	Iteration(graph *Graph, stats IterationStats)
+	// Complete is called after convergence, before negative nodes are merged into the results
+	Complete(graph *Graph, stats IterationStats)
+}
+
+// negConsumerRank returns the current rank of the negConsumer node or 0 if it's not part of the graph
+func (graph Graph) negConsumerRank() float64 {
+	if node, ok := graph.Nodes[graph.NegConsumer.ID]; ok {
+		return node.PRank
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package rep
+
+import "testing"
+
+type testObserver struct {
+	starts     int
+	iterations []IterationStats
+	complete   *IterationStats
+}
+
+func (o *testObserver) Start(graph *Graph) { o.starts++ }
+
+func (o *testObserver) Iteration(graph *Graph, stats IterationStats) {
+	o.iterations = append(o.iterations, stats)
+}
+
+func (o *testObserver) Complete(graph *Graph, stats IterationStats) { o.complete = &stats }
+
+func TestObserver(t *testing.T) {
+	graph := NewGraph(0.85, 0.000001, 0)
+	observer := &testObserver{}
+	graph.Observer = observer
+
+	a := NewNode("a", 0, 0)
+	b := NewNode("b", 0, 0)
+	c := NewNode("c", 0, 0)
+
+	graph.AddPersonalizationNode(a)
+	graph.Link(a, b, 1.0)
+	graph.Link(a, c, -1.0)
+
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+
+	if observer.starts != 1 {
+		t.Errorf("expected 1 start but got %d", observer.starts)
+	}
+	if len(observer.iterations) == 0 || observer.complete == nil {
+		t.Fatal("expected iterations and completion to be observed")
+	}
+
+	for i, stats := range observer.iterations {
+		if stats.Iteration != i+1 {
+			t.Errorf("expected iteration %d but got %d", i+1, stats.Iteration)
+		}
+	}
+
+	last := observer.iterations[len(observer.iterations)-1]
+	if *observer.complete != last {
+		t.Error("Expected", last, "but got", *observer.complete)
+	}
+	if last.Delta > 0.000001 {
+		t.Errorf("last Δ %f should be below ε", last.Delta)
+	}
+	// b and c are dangling
+	if last.DanglingWeight == 0 {
+		t.Errorf("expected dangling weight")
+	}
+}
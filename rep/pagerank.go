@@ -9,32 +9,59 @@ import (
 // ε (epsilon) is the convergence criteria, usually set to a tiny value.
 //
 // This method will run as many iterations as needed, until the graph converges.
-func (graph Graph) Rank(callback func(key string, pRank float64, nRank float64)) {
+func (graph *Graph) Rank(callback func(key string, pRank float64, nRank float64)) {
+	// Rank modifies the graph, keep its inputs for what-if simulations if asked to
+	var inputs *Graph
+	if graph.RetainInputs {
+		inputs = graph.Clone()
+	}
+
 	graph.Finalize()
 
-	Δ := float64(1.0)
 	N := float64(len(graph.Nodes))
-	pVector := graph.Params.Personalization
-	ε := graph.Params.ε
-	α := graph.Params.α
-
-	personalized := len(pVector) > 0
 
 	// these are personlaization node weights
 	// we adjust them so that all p nodes have the same outgoing link weight
 	pWeights := graph.initPersonalizationNodes()
 
-	// Normalize all the edge weights so that their sum amounts to 1.
-	for source := range graph.Nodes {
-		if graph.Nodes[source].degree > 0 {
-			for target := range graph.Edges[source] {
-				graph.Edges[source][target] /= graph.Nodes[source].degree
-			}
-		}
-	}
+	graph.normalizeEdges()
 
 	graph.initScores(N, pWeights)
 
+	if graph.Observer != nil {
+		graph.Observer.Start(graph)
+	}
+
+	var stats IterationStats
+	if graph.Solver == PowerIteration {
+		stats = graph.powerIteration(N, pWeights)
+	} else {
+		stats = graph.solve(pWeights)
+	}
+
+	if graph.Observer != nil {
+		graph.Observer.Complete(graph, stats)
+	}
+
+	// keep what we need to explain the results
+	keys := make([]string, 0, len(graph.Nodes))
+	for key := range graph.Nodes {
+		keys = append(keys, key)
+	}
+	graph.rankState = &rankState{n: N, keys: keys, pWeights: pWeights, stats: stats, inputs: inputs}
+
+	graph.processResults(callback)
+}
+
+// powerIteration repeats the pagerank iteration until the graph converges
+func (graph *Graph) powerIteration(N float64, pWeights []float64) IterationStats {
+	var stats IterationStats
+	Δ := float64(1.0)
+	pVector := graph.Params.Personalization
+	ε := graph.Params.ε
+	α := graph.Params.α
+	personalized := len(pVector) > 0
+
 	iter := 0
 	for Δ > ε {
 		danglingWeight := float64(0)
@@ -76,10 +103,29 @@ func (graph Graph) Rank(callback func(key string, pRank float64, nRank float64))
 			Δ += math.Abs(value.PRank - nodes[key])
 		}
 		iter++
+
+		stats = IterationStats{
+			Iteration:       iter,
+			Delta:           Δ,
+			DanglingWeight:  danglingWeight,
+			NegConsumerRank: graph.negConsumerRank(),
+		}
+		if graph.Observer != nil {
+			graph.Observer.Iteration(graph, stats)
+		}
 	}
+	return stats
+}
 
-	// fmt.Println("iterations:", iter, "Δ", Δ)
-	graph.processResults(callback)
+// normalizeEdges normalizes all the edge weights so that their sum amounts to 1
+func (graph *Graph) normalizeEdges() {
+	for source := range graph.Nodes {
+		if graph.Nodes[source].degree > 0 {
+			for target := range graph.Edges[source] {
+				graph.Edges[source][target] /= graph.Nodes[source].degree
+			}
+		}
+	}
 }
 
 // make sure the total start sum of all scores is 1
@@ -115,10 +161,25 @@ func (graph Graph) initScores(N float64, pWeights []float64) {
 // we also update start scores here
 func (graph Graph) initPersonalizationNodes() []float64 {
 	pVector := graph.Params.Personalization
+	pWeights := graph.personalizationWeights(pVector)
+
+	var scoreSum float64
+	for _, key := range pVector {
+		scoreSum += graph.Nodes[key].PRank
+	}
+
+	for i, key := range pVector {
+		graph.Nodes[key].PRank = scoreSum * pWeights[i]
+	}
+
+	return pWeights
+}
+
+// personalizationWeights returns the normalized weight of each personalization node
+func (graph Graph) personalizationWeights(pVector []string) []float64 {
 	pWeights := make([]float64, len(pVector))
 
 	var pWeightsSum float64
-	var scoreSum float64
 	for i, key := range pVector {
 		var d float64
 		// root node score and weight should not be 0
@@ -127,13 +188,11 @@ func (graph Graph) initPersonalizationNodes() []float64 {
 		}
 		pWeights[i] = d
 		pWeightsSum += d
-		scoreSum += graph.Nodes[key].PRank
 	}
 
 	// normalize personalization weights
-	for i, key := range pVector {
+	for i := range pVector {
 		pWeights[i] /= pWeightsSum
-		graph.Nodes[key].PRank = scoreSum * pWeights[i]
 	}
 
 	return pWeights
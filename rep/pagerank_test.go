@@ -1,8 +1,12 @@
 package rep
 
 import (
+	"math"
+	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/relevant-community/reputation/exact"
 )
 
 type Result struct {
@@ -195,6 +199,38 @@ func TestCancelOpposites(t *testing.T) {
 	}
 }
 
+func TestCancelOppositesDegree(t *testing.T) {
+	// the cancelled weight is removed from the degree of the source, so a -> b and a -> c get 1/2 each
+	// before cancelOpposites took the source node by pointer the degree stayed at 4 and they got 1/4
+	graph := NewGraph(0.5, 1e-12, 0)
+
+	a := NewNode("a", 0, 0)
+	b := NewNode("b", 0, 0)
+	c := NewNode("c", 0, 0)
+
+	graph.AddPersonalizationNode(a)
+	graph.Link(a, b, 2.0)
+	graph.Link(a, b, -1.0)
+	graph.Link(a, c, 1.0)
+
+	if degree := graph.Nodes["a"].degree; degree != 2 {
+		t.Fatalf("the degree of a should be 2, got %f", degree)
+	}
+
+	actual := map[string]Result{}
+	graph.Rank(func(id string, pRank float64, nRank float64) {
+		actual[id] = Result{pRank: pRank, nRank: nRank}
+	})
+
+	// b = c = α a / 2, a = (1 - α) + α (b + c) => a = 2/3, b = c = 1/6
+	expected := map[string]float64{"a": 2.0 / 3, "b": 1.0 / 6, "c": 1.0 / 6}
+	for id, e := range expected {
+		if math.Abs(actual[id].pRank-e) > 1e-9 {
+			t.Errorf("%s expected %f but got %f", id, e, actual[id].pRank)
+		}
+	}
+}
+
 func TestNegativeLink(t *testing.T) {
 	graph := NewGraph(0.85, 0.000001, 0)
 
@@ -376,3 +412,83 @@ func TestMaxNeg(t *testing.T) {
 		t.Errorf("weight of neg node should decrease %f, %f", eRank, actual["e"].pRank)
 	}
 }
+
+type exactLink struct {
+	source, target string
+	weight         float64
+}
+
+// exactGraphs are small graphs to compare with the exact ranks, personalized by "a" if personalized is true
+var exactGraphs = []struct {
+	name         string
+	personalized bool
+	links        []exactLink
+}{
+	{"circle", false, []exactLink{{"a", "b", 1}, {"b", "c", 1}, {"c", "d", 1}, {"d", "a", 1}}},
+	{"weighted", false, []exactLink{{"a", "b", 1}, {"a", "c", 3}, {"b", "c", 1}, {"c", "a", 2}, {"c", "d", 1}}},
+	{"personalized", true, []exactLink{{"a", "b", 2}, {"a", "c", 1}, {"b", "c", 1}, {"c", "d", 1}, {"d", "b", 1}}},
+	{"negative", true, []exactLink{{"a", "b", 2}, {"a", "c", 1}, {"c", "d", 1}, {"b", "d", -1}, {"a", "e", -1}, {"d", "e", 1}}},
+	{"cancelled", true, []exactLink{{"a", "b", 2}, {"a", "b", -1}, {"a", "c", 1}, {"b", "c", -3}, {"b", "c", 1}, {"c", "a", 1}}},
+	{"negConsumer", true, []exactLink{
+		{"a", "b", 2}, {"a", "c", 1}, {"b", "c", -1}, {"b", "a", 1}, {"c", "d", -1},
+		{"c", "e", 3}, {"d", "e", 3}, {"e", "b", 1}, {"e", "d", -2},
+	}},
+}
+
+// rankExact returns the exact ranks of a graph with the cached ranks of results
+func rankExact(t *testing.T, α float64, personalized bool, links []exactLink, results map[string]Ranks) map[string]exact.Result {
+	rat := func(f float64) *big.Rat { return new(big.Rat).SetFloat64(f) }
+	graph := exact.NewGraph(rat(α), rat(results[NegConsumerID].PRank))
+	node := func(id string) exact.Node { return exact.NewNode(id, rat(results[id].PRank), rat(results[id].NRank)) }
+	if personalized {
+		graph.AddPersonalizationNode(node("a"))
+	}
+	for _, link := range links {
+		graph.Link(node(link.source), node(link.target), rat(link.weight))
+	}
+	expected, err := graph.Rank()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expected
+}
+
+// TestExact compares the ranks with the exact stationary vector of the same graph
+// power iteration stops once Δ < ε, the remaining error is at most about ε α / (1 - α)
+func TestExact(t *testing.T) {
+	α, ε := 0.85, 1e-8
+	for _, test := range exactGraphs {
+		results := map[string]Ranks{}
+		// the second pass uses the cached ranks of the first one, like in TestNegativeLink
+		for pass := 0; pass < 2; pass++ {
+			expected := rankExact(t, α, test.personalized, test.links, results)
+
+			graph := NewGraph(α, ε, results[NegConsumerID].PRank)
+			node := func(id string) Node { return NewNode(id, results[id].PRank, results[id].NRank) }
+			if test.personalized {
+				graph.AddPersonalizationNode(node("a"))
+			}
+			for _, link := range test.links {
+				graph.Link(node(link.source), node(link.target), link.weight)
+			}
+			results = map[string]Ranks{}
+			graph.Rank(func(id string, pRank float64, nRank float64) {
+				results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+			})
+
+			if len(results) != len(expected) {
+				t.Fatalf("%s pass %d: expected %d results, got %d", test.name, pass, len(expected), len(results))
+			}
+			var maxError float64
+			for id, e := range expected {
+				p, _ := e.PRank.Float64()
+				n, _ := e.NRank.Float64()
+				maxError = math.Max(maxError, math.Max(math.Abs(results[id].PRank-p), math.Abs(results[id].NRank-n)))
+			}
+			t.Logf("%s pass %d: max error %.3g", test.name, pass, maxError)
+			if bound := ε * α / (1 - α); maxError > bound {
+				t.Errorf("%s pass %d: error %g is larger than %g", test.name, pass, maxError, bound)
+			}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package rep
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNotPersonalized is returned by queries that need a personalization vector
+var ErrNotPersonalized = errors.New("graph has no personalization nodes")
+
+// ErrInvalidPathLimit is returned by TracePaths when k is not positive or maxDepth is negative
+var ErrInvalidPathLimit = errors.New("k must be positive and maxDepth can't be negative")
+
+// Hop is a single link of a trust path
+type Hop struct {
+	Source   string
+	Target   string
+	Weight   float64 // normalized weight of the link
+	Negative bool    // true if this is a negative link (downvote)
+}
+
+// Path is a chain of links from a personalization node to a target node
+type Path struct {
+	Seed string
+	Hops []Hop
+	// Flow is the rank of the seed multiplied by α · weight of every hop
+	// this is how much of the seed's rank reaches the target along this path
+	Flow float64
+}
+
+// TracePaths returns the k paths that carry the most rank from the personalization nodes to the target
+// paths are sorted by flow, highest first
+// paths ending with a negative link contribute to the target's negative rank
+// maxDepth limits the number of hops, 0 means no limit
+// without a limit the search can still visit many paths in dense graphs, so prefer a small maxDepth
+// it must be called after Rank
+func (graph *Graph) TracePaths(target string, k int, maxDepth int) ([]Path, error) {
+	if k <= 0 || maxDepth < 0 {
+		return nil, ErrInvalidPathLimit
+	}
+	if graph.rankState == nil {
+		return nil, ErrNotRanked
+	}
+	if len(graph.Params.Personalization) == 0 {
+		return nil, ErrNotPersonalized
+	}
+
+	posKey := getKey(target, Positive)
+	negKey := getKey(target, Negative)
+	_, isPos := graph.Nodes[posKey]
+	_, isNeg := graph.NegNodes[negKey]
+	if !isPos && !isNeg {
+		return nil, fmt.Errorf("node %s not found", target)
+	}
+
+	α := graph.Params.α
+	paths := []Path{}
+
+	// only nodes that have a path to the target are explored
+	reaches := graph.reaching(posKey, negKey)
+
+	queue := &pathQueue{}
+	seen := map[string]bool{}
+	for _, seed := range graph.Params.Personalization {
+		// seeds can be listed more than once
+		if seen[seed] || !reaches[seed] {
+			continue
+		}
+		seen[seed] = true
+		heap.Push(queue, &partialPath{
+			Path: Path{Seed: seed, Flow: graph.Nodes[seed].PRank},
+			key:  seed,
+		})
+	}
+
+	// flows of the best k paths that reached the target so far, lowest first
+	// a partial path with less flow than all of them can't make it into the results
+	best := make([]float64, 0, k)
+	threshold := func() float64 {
+		if len(best) < k {
+			return 0
+		}
+		return best[0]
+	}
+	addBest := func(flow float64) {
+		if len(best) == k {
+			if flow <= best[0] {
+				return
+			}
+			best = best[1:]
+		}
+		i := sort.SearchFloat64s(best, flow)
+		best = append(best, 0)
+		copy(best[i+1:], best[i:])
+		best[i] = flow
+	}
+
+	// flow never increases as a path grows (α and normalized weights are <= 1)
+	// so paths are completed in order of their flow
+	for queue.Len() > 0 && len(paths) < k {
+		current := heap.Pop(queue).(*partialPath)
+
+		if current.key == posKey || current.key == negKey {
+			paths = append(paths, current.Path)
+			continue
+		}
+		if maxDepth > 0 && len(current.Hops) >= maxDepth {
+			continue
+		}
+
+		for next, weight := range graph.Edges[current.key] {
+			if !reaches[next] || weight == 0 {
+				continue
+			}
+			negNode, negative := graph.NegNodes[next]
+			id := next
+			if negative {
+				id = negNode.ID
+			}
+			// negative nodes have no outgoing links, only follow them to the target
+			if negative && next != negKey {
+				continue
+			}
+			flow := current.Flow * α * weight
+			if flow < threshold() || current.visits(id) {
+				continue
+			}
+			if next == posKey || next == negKey {
+				addBest(flow)
+			}
+
+			hops := make([]Hop, len(current.Hops), len(current.Hops)+1)
+			copy(hops, current.Hops)
+			hops = append(hops, Hop{Source: current.key, Target: id, Weight: weight, Negative: negative})
+
+			heap.Push(queue, &partialPath{
+				Path: Path{Seed: current.Seed, Hops: hops, Flow: flow},
+				key:  next,
+			})
+		}
+	}
+
+	return paths, nil
+}
+
+// reaching returns the keys of the nodes that have a path to one of the targets, including the targets
+func (graph *Graph) reaching(targets ...string) map[string]bool {
+	in := map[string][]string{}
+	for source, edges := range graph.Edges {
+		for target := range edges {
+			in[target] = append(in[target], source)
+		}
+	}
+
+	reaches := map[string]bool{}
+	stack := []string{}
+	for _, target := range targets {
+		if !reaches[target] {
+			reaches[target] = true
+			stack = append(stack, target)
+		}
+	}
+	for len(stack) > 0 {
+		key := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, source := range in[key] {
+			if !reaches[source] {
+				reaches[source] = true
+				stack = append(stack, source)
+			}
+		}
+	}
+	return reaches
+}
+
+// partialPath is a path that is still being explored
+type partialPath struct {
+	Path
+	key string // graph key of the last node
+}
+
+// visits returns true if the node with the given id is already in the path
+// paths are short, so scanning the hops is cheaper than copying a set for every path
+func (p *partialPath) visits(id string) bool {
+	if p.Seed == id {
+		return true
+	}
+	for _, hop := range p.Hops {
+		if hop.Target == id {
+			return true
+		}
+	}
+	return false
+}
+
+// pathQueue is a max-heap of partial paths ordered by flow
+type pathQueue []*partialPath
+
+func (q pathQueue) Len() int { return len(q) }
+
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].Flow != q[j].Flow {
+		return q[i].Flow > q[j].Flow
+	}
+	// prefer shorter paths so the order is stable
+	if len(q[i].Hops) != len(q[j].Hops) {
+		return len(q[i].Hops) < len(q[j].Hops)
+	}
+	return q[i].key < q[j].key
+}
+
+func (q pathQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(*partialPath)) }
+
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
@@ -0,0 +1,126 @@
+package rep
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestTracePaths(t *testing.T) {
+	graph, results := rankTwice(func(graph *Graph, n map[string]Node) {
+		graph.Link(n["a"], n["b"], 1.0)
+		graph.Link(n["a"], n["c"], 2.0)
+		graph.Link(n["c"], n["d"], 1.0)
+		graph.Link(n["b"], n["d"], -1.0)
+		graph.Link(n["d"], n["e"], 1.0)
+	})
+
+	paths, err := graph.TracePaths("d", 5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 paths but got %d", len(paths))
+	}
+
+	// a -> c carries twice the weight of a -> b
+	best := paths[0]
+	if len(best.Hops) != 2 || best.Hops[0].Target != "c" || best.Hops[1].Negative {
+		t.Errorf("expected a -> c -> d to be the best path but got %v", best.Hops)
+	}
+	expectedFlow := results["a"].PRank * 0.85 * (2.0 / 3.0) * 0.85 * 1.0
+	if math.Abs(best.Flow-expectedFlow) > 1e-9 {
+		t.Errorf("expected flow %f but got %f", expectedFlow, best.Flow)
+	}
+
+	negative := paths[1]
+	if negative.Seed != "a" || !negative.Hops[1].Negative || negative.Hops[1].Target != "d" {
+		t.Errorf("expected a -> b -x d to be a negative path but got %v", negative.Hops)
+	}
+
+	// d links to e, but some of d's weight goes to the negConsumer
+	paths, err = graph.TracePaths("e", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || len(paths[0].Hops) != 3 || paths[0].Hops[2].Weight >= 1 {
+		t.Errorf("expected a single path to e with a reduced last hop %v", paths)
+	}
+
+	// depth limit
+	paths, _ = graph.TracePaths("e", 1, 2)
+	if len(paths) != 0 {
+		t.Errorf("expected no paths within 2 hops but got %v", paths)
+	}
+
+	// the seed reaches itself
+	paths, _ = graph.TracePaths("a", 1, 0)
+	if len(paths) != 1 || len(paths[0].Hops) != 0 || paths[0].Flow != results["a"].PRank {
+		t.Errorf("expected an empty path to the seed %v", paths)
+	}
+}
+
+func TestTracePathsErrors(t *testing.T) {
+	graph := NewGraph(0.85, 0.000001, 0)
+	graph.Link(NewNode("a", 0, 0), NewNode("b", 0, 0), 1.0)
+
+	if _, err := graph.TracePaths("b", 1, 0); err != ErrNotRanked {
+		t.Errorf("expected ErrNotRanked but got %v", err)
+	}
+
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+
+	if _, err := graph.TracePaths("b", 1, 0); err != ErrNotPersonalized {
+		t.Errorf("expected ErrNotPersonalized but got %v", err)
+	}
+
+	for _, limits := range [][2]int{{0, 0}, {-1, 0}, {1, -1}} {
+		if _, err := graph.TracePaths("b", limits[0], limits[1]); err != ErrInvalidPathLimit {
+			t.Errorf("expected ErrInvalidPathLimit for k %d maxDepth %d but got %v", limits[0], limits[1], err)
+		}
+	}
+}
+
+// completeGraph links every pair of n nodes, seeded by n0, plus a node z that only links to n0
+// there are more than n! simple paths between two nodes, too many to enumerate
+func completeGraph(n int) *Graph {
+	graph := NewGraph(0.85, 0.000001, 0)
+	graph.AddPersonalizationNode(NewNode("n0", 0, 0))
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j {
+				graph.Link(NewNode(fmt.Sprintf("n%d", i), 0, 0), NewNode(fmt.Sprintf("n%d", j), 0, 0), float64(1+(i+j)%3))
+			}
+		}
+	}
+	graph.Link(NewNode("z", 0, 0), NewNode("n0", 0, 0), 1)
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+	return graph
+}
+
+func TestTracePathsUnreachable(t *testing.T) {
+	graph := completeGraph(20)
+	paths, err := graph.TracePaths("z", 5, 0)
+	if err != nil || len(paths) != 0 {
+		t.Errorf("expected no paths to z but got %v %v", paths, err)
+	}
+}
+
+func TestTracePathsDense(t *testing.T) {
+	graph := completeGraph(20)
+	paths, err := graph.TracePaths("n1", 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 10 {
+		t.Fatalf("expected 10 paths but got %d", len(paths))
+	}
+	if len(paths[0].Hops) != 1 {
+		t.Errorf("expected the direct link to be the best path but got %v", paths[0].Hops)
+	}
+	for i := 1; i < len(paths); i++ {
+		if paths[i].Flow > paths[i-1].Flow {
+			t.Errorf("paths should be sorted by flow %v", paths)
+		}
+	}
+}
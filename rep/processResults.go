@@ -1,5 +1,12 @@
 package rep
 
+// Ranks holds the computed ranks of a single node
+type Ranks struct {
+	ID    string  `json:"id"`
+	PRank float64 `json:"pRank"`
+	NRank float64 `json:"nRank"`
+}
+
 func (graph Graph) processResults(callback func(id string, pRank float64, nRank float64)) {
 	graph.mergeNegatives()
 	for key, node := range graph.Nodes {
@@ -0,0 +1,111 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPushAlpha is returned by Push when α is 1, without random jumps the residuals never settle
+var ErrPushAlpha = errors.New("push needs α < 1")
+
+// Push approximates the ranks of the graph personalized by source alone, without ranking the whole graph
+// it uses the forward push algorithm of Andersen, Chung and Lang (Local Graph Partitioning using PageRank Vectors, 2006):
+// every node keeps an estimate and a residual, a node with a residual of at least threshold times its number of
+// outgoing links keeps (1 - α) of it and pushes the rest along its links, so only the neighbourhood of source is visited
+//
+// the results match Rank with source as the only personalization node, the total error is the residual left over,
+// at most threshold times the number of links of the visited nodes. negative links push to the negative nodes
+// and the negConsumer takes its share of the outgoing weight of nodes with a cached negative rank, like in Rank.
+// dangling nodes push their residual back to source. only visited nodes are returned
+//
+// it must be called before Rank, the graph is not modified
+func (graph *Graph) Push(source string, threshold float64) (map[string]Ranks, error) {
+	if graph.rankState != nil {
+		return nil, ErrRanked
+	}
+	if graph.Params.α >= 1 {
+		return nil, ErrPushAlpha
+	}
+	if threshold <= 0 {
+		return nil, fmt.Errorf("threshold must be positive, got %g", threshold)
+	}
+	if node, ok := graph.Nodes[source]; !ok || node.nodeType != Positive {
+		return nil, fmt.Errorf("node %s not found", source)
+	}
+
+	α := graph.Params.α
+	estimate := map[string]float64{}
+	residual := map[string]float64{source: 1}
+	queue := []string{source}
+	queued := map[string]bool{source: true}
+
+	add := func(key string, rank float64) {
+		residual[key] += rank
+		if !queued[key] {
+			queued[key] = true
+			queue = append(queue, key)
+		}
+	}
+
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		queued[key] = false
+
+		r := residual[key]
+		links, negWeight := graph.pushLinks(key)
+		if r < threshold*float64(links) {
+			continue
+		}
+		residual[key] = 0
+		estimate[key] += (1 - α) * r
+
+		node, ok := graph.Nodes[key]
+		if !ok || node.degree == 0 {
+			// dangling rank goes back to the seed like in Rank
+			add(source, α*r)
+			continue
+		}
+		degree := node.degree + negWeight
+		for target, weight := range graph.Edges[key] {
+			add(target, α*r*weight/degree)
+		}
+		if negWeight > 0 {
+			add(graph.NegConsumer.ID, α*r*negWeight/degree)
+		}
+	}
+
+	results := map[string]Ranks{}
+	for key, rank := range estimate {
+		id, nodeType := graph.NegConsumer.ID, Positive
+		if node, ok := graph.Nodes[key]; ok {
+			id, nodeType = node.ID, node.nodeType
+		}
+		result := results[id]
+		result.ID = id
+		if nodeType == Negative {
+			result.NRank = rank
+		} else {
+			result.PRank = rank
+		}
+		results[id] = result
+	}
+	return results, nil
+}
+
+// pushLinks returns the number of outgoing links of a node, at least 1, and the weight of its negConsumer link
+// the negConsumer link is computed from the cached ranks since the graph is not finalized
+func (graph *Graph) pushLinks(key string) (int, float64) {
+	links := len(graph.Edges[key])
+	var negWeight float64
+	if negNode, ok := graph.NegNodes[getKey(key, Negative)]; ok {
+		if weight, ok := graph.negWeight(negNode); ok {
+			negWeight = weight
+			links++
+		}
+	}
+	if links == 0 {
+		links = 1
+	}
+	return links, negWeight
+}
@@ -0,0 +1,91 @@
+package rep
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// pushGraph builds the links with the cached ranks of results and "a" as the only seed
+func pushGraph(α float64, links []exactLink, cached map[string]Ranks) *Graph {
+	graph := NewGraph(α, 1e-10, cached[NegConsumerID].PRank)
+	node := func(id string) Node { return NewNode(id, cached[id].PRank, cached[id].NRank) }
+	graph.AddPersonalizationNode(node("a"))
+	for _, link := range links {
+		graph.Link(node(link.source), node(link.target), link.weight)
+	}
+	return graph
+}
+
+func TestPushExact(t *testing.T) {
+	α, threshold := 0.85, 1e-12
+	for _, test := range exactGraphs {
+		cached := map[string]Ranks{}
+		// the second pass uses cached negative ranks, so nodes get negConsumer links
+		for pass := 0; pass < 2; pass++ {
+			expected := rankExact(t, α, true, test.links, cached)
+			results, err := pushGraph(α, test.links, cached).Push("a", threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var worst float64
+			for id, e := range expected {
+				p, _ := e.PRank.Float64()
+				n, _ := e.NRank.Float64()
+				worst = math.Max(worst, math.Max(math.Abs(results[id].PRank-p), math.Abs(results[id].NRank-n)))
+			}
+			if worst > 1e-9 {
+				t.Errorf("%s pass %d: error %g", test.name, pass, worst)
+			}
+
+			cached = map[string]Ranks{}
+			pushGraph(α, test.links, cached).Rank(func(id string, pRank float64, nRank float64) {
+				cached[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+			})
+		}
+	}
+}
+
+func TestPushLocal(t *testing.T) {
+	graph := pushGraph(0.85, []exactLink{{"a", "b", 1}, {"b", "a", 1}, {"a", "c", -1}}, nil)
+	// a large component that can't be reached from a
+	for i := 0; i < 1000; i++ {
+		graph.Link(NewNode(fmt.Sprintf("n%d", i), 0, 0), NewNode(fmt.Sprintf("n%d", (i*7+1)%1000), 0, 0), 1)
+	}
+	hash := mustHash(t, graph)
+
+	results, err := graph.Push("a", 1e-6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Errorf("expected the 3 nodes reachable from a, got %v", results)
+	}
+	if results["c"].NRank == 0 || results["c"].PRank != 0 {
+		t.Errorf("c should only have a negative rank, got %v", results["c"])
+	}
+	if !bytes.Equal(hash, mustHash(t, graph)) {
+		t.Error("push should not modify the graph")
+	}
+}
+
+func TestPushErrors(t *testing.T) {
+	links := []exactLink{{"a", "b", 1}}
+	if _, err := pushGraph(1, links, nil).Push("a", 1e-6); err != ErrPushAlpha {
+		t.Errorf("expected ErrPushAlpha, got %v", err)
+	}
+	if _, err := pushGraph(0.85, links, nil).Push("x", 1e-6); err == nil {
+		t.Error("expected an error for an unknown node")
+	}
+	if _, err := pushGraph(0.85, links, nil).Push("a", 0); err == nil {
+		t.Error("expected an error for a threshold of 0")
+	}
+
+	graph := pushGraph(0.85, links, nil)
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+	if _, err := graph.Push("a", 1e-6); err != ErrRanked {
+		t.Errorf("expected ErrRanked, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package rep
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Snapshot is a serializable description of a graph's inputs
+// it is used to store and share community graphs as json files
+type Snapshot struct {
+	Alpha           float64        `json:"alpha"`
+	Epsilon         float64        `json:"epsilon"`
+	NegConsumerRank float64        `json:"negConsumerRank"`
+	Personalization []string       `json:"personalization"`
+	Nodes           []SnapshotNode `json:"nodes"`
+	Links           []SnapshotLink `json:"links"`
+	Solver          Solver         `json:"solver,omitempty"`
+}
+
+// SnapshotNode holds the cached ranks of a node
+// nodes that only appear in links don't need to be listed
+type SnapshotNode struct {
+	ID    string  `json:"id"`
+	PRank float64 `json:"pRank"`
+	NRank float64 `json:"nRank"`
+}
+
+// SnapshotLink is a signed link between two nodes
+type SnapshotLink struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Weight float64 `json:"weight"`
+}
+
+// ReadSnapshot decodes a json snapshot
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Write encodes the snapshot as json
+func (snapshot Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snapshot)
+}
+
+// Linker is a graph that personalization nodes and links can be added to,
+// like Graph or the graphs of the eigentrust and srwr packages
+type Linker interface {
+	AddPersonalizationNode(pNode Node)
+	Link(source, target Node, weight float64)
+}
+
+// Graph builds a new graph from the snapshot
+func (snapshot Snapshot) Graph() *Graph {
+	graph := NewGraph(snapshot.Alpha, snapshot.Epsilon, snapshot.NegConsumerRank)
+	graph.Solver = snapshot.Solver
+	snapshot.Load(graph)
+	return graph
+}
+
+// Load adds the personalization nodes and the links of the snapshot to graph
+// nodes carry their cached ranks, nodes that are not listed have ranks of 0
+func (snapshot Snapshot) Load(graph Linker) {
+	nodes := map[string]Node{}
+	for _, node := range snapshot.Nodes {
+		nodes[node.ID] = NewNode(node.ID, node.PRank, node.NRank)
+	}
+	getNode := func(id string) Node {
+		if node, ok := nodes[id]; ok {
+			return node
+		}
+		return NewNode(id, 0, 0)
+	}
+
+	for _, id := range snapshot.Personalization {
+		graph.AddPersonalizationNode(getNode(id))
+	}
+	for _, link := range snapshot.Links {
+		graph.Link(getNode(link.Source), getNode(link.Target), link.Weight)
+	}
+}
+
+// WithResults returns a copy of the snapshot that uses results as the cached ranks
+// this is how we run the second pass of the algorithm
+func (snapshot Snapshot) WithResults(results map[string]Ranks) Snapshot {
+	next := snapshot
+	next.NegConsumerRank = results[NegConsumerID].PRank
+	next.Nodes = make([]SnapshotNode, 0, len(snapshot.Nodes))
+
+	listed := map[string]bool{}
+	for _, node := range snapshot.Nodes {
+		listed[node.ID] = true
+		result := results[node.ID]
+		next.Nodes = append(next.Nodes, SnapshotNode{ID: node.ID, PRank: result.PRank, NRank: result.NRank})
+	}
+	for _, link := range snapshot.Links {
+		for _, id := range []string{link.Source, link.Target} {
+			if result, ok := results[id]; ok && !listed[id] {
+				listed[id] = true
+				next.Nodes = append(next.Nodes, SnapshotNode{ID: id, PRank: result.PRank, NRank: result.NRank})
+			}
+		}
+	}
+	return next
+}
+
+// Rank ranks the snapshot graph and collects the results
+// when passes > 1 the results of each pass are used as cached ranks for the next one
+// so the negative ranks can modulate the outgoing links
+// it returns the graph used in the last pass along with its results
+func (snapshot Snapshot) Rank(passes int) (*Graph, map[string]Ranks) {
+	return snapshot.RankObserved(passes, nil)
+}
+
+// RankObserved is like Rank but attaches the observer to the graph of every pass
+func (snapshot Snapshot) RankObserved(passes int, observer Observer) (*Graph, map[string]Ranks) {
+	graph := snapshot.Graph()
+	results := map[string]Ranks{}
+	for pass := 0; pass < passes; pass++ {
+		if pass > 0 {
+			graph = snapshot.WithResults(results).Graph()
+		}
+		graph.Observer = observer
+		results = map[string]Ranks{}
+		graph.Rank(func(id string, pRank float64, nRank float64) {
+			results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+		})
+	}
+	return graph, results
+}
+
+// ReadResults decodes a json array of results into a map keyed by id
+func ReadResults(r io.Reader) (map[string]Ranks, error) {
+	var list []Ranks
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+	results := make(map[string]Ranks, len(list))
+	for _, result := range list {
+		results[result.ID] = result
+	}
+	return results, nil
+}
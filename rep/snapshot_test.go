@@ -0,0 +1,79 @@
+package rep
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	snapshot := Snapshot{
+		Alpha:           0.85,
+		Epsilon:         0.000001,
+		Personalization: []string{"a"},
+		Nodes:           []SnapshotNode{{ID: "a", PRank: 0.5, NRank: 0}},
+		Links: []SnapshotLink{
+			{Source: "a", Target: "b", Weight: 2.0},
+			{Source: "b", Target: "c", Weight: -1.0},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(*decoded, snapshot) != true {
+		t.Error("Expected", snapshot, "but got", *decoded)
+	}
+}
+
+func TestSnapshotRank(t *testing.T) {
+	snapshot := Snapshot{
+		Alpha:           0.85,
+		Epsilon:         0.000001,
+		Personalization: []string{"a"},
+		Links: []SnapshotLink{
+			{Source: "a", Target: "b", Weight: 1.0},
+			{Source: "a", Target: "c", Weight: 2.0},
+			{Source: "c", Target: "d", Weight: 1.0},
+			{Source: "b", Target: "d", Weight: -1.0},
+			{Source: "d", Target: "e", Weight: 1.0},
+		},
+	}
+
+	_, first := snapshot.Rank(1)
+	_, second := snapshot.Rank(2)
+
+	// same as TestNegativeConsumer
+	if first["e"].PRank <= second["e"].PRank {
+		t.Errorf("weight of neg node should decrease %f, %f", first["e"].PRank, second["e"].PRank)
+	}
+
+	next := snapshot.WithResults(first)
+	if next.NegConsumerRank != first[NegConsumerID].PRank {
+		t.Errorf("expected negConsumer rank %f but got %f", first[NegConsumerID].PRank, next.NegConsumerRank)
+	}
+	if len(next.Nodes) != 5 {
+		t.Errorf("expected cached ranks for 5 nodes but got %d", len(next.Nodes))
+	}
+}
+
+func TestReadResults(t *testing.T) {
+	input := `[{"id": "a", "pRank": 0.75, "nRank": 0}, {"id": "b", "pRank": 0.25, "nRank": 0.1}]`
+	results, err := ReadResults(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]Ranks{
+		"a": {ID: "a", PRank: 0.75, NRank: 0},
+		"b": {ID: "b", PRank: 0.25, NRank: 0.1},
+	}
+	if reflect.DeepEqual(results, expected) != true {
+		t.Error("Expected", expected, "but got", results)
+	}
+}
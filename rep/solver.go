@@ -0,0 +1,304 @@
+package rep
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Solver is the method Rank uses to find the ranks
+// all solvers stop once the ranks change by less than ε (Δ, the sum of absolute changes) in an iteration
+type Solver int
+
+const (
+	// PowerIteration repeats the pagerank iteration, this is the default
+	PowerIteration Solver = iota
+	// Jacobi solves the pagerank linear system with Jacobi iterations
+	Jacobi
+	// GaussSeidel solves the pagerank linear system with Gauss-Seidel sweeps
+	// ranks updated in a sweep are used right away, which usually halves the number of iterations
+	GaussSeidel
+	// Extrapolation is power iteration with a quadratic extrapolation every ExtrapolationInterval iterations
+	Extrapolation
+)
+
+// ExtrapolationInterval is the number of power iterations between two extrapolations
+const ExtrapolationInterval = 10
+
+var solverNames = []string{"power", "jacobi", "gauss-seidel", "extrapolation"}
+
+func (solver Solver) String() string {
+	if solver < 0 || int(solver) >= len(solverNames) {
+		return fmt.Sprintf("Solver(%d)", int(solver))
+	}
+	return solverNames[solver]
+}
+
+// ParseSolver returns the solver with the given name: power, jacobi, gauss-seidel or extrapolation
+func ParseSolver(name string) (Solver, error) {
+	for i, n := range solverNames {
+		if n == name {
+			return Solver(i), nil
+		}
+	}
+	return PowerIteration, fmt.Errorf("unknown solver %q", name)
+}
+
+// MarshalText encodes the solver as its name
+func (solver Solver) MarshalText() ([]byte, error) {
+	return []byte(solver.String()), nil
+}
+
+// UnmarshalText decodes a solver name
+func (solver *Solver) UnmarshalText(text []byte) error {
+	s, err := ParseSolver(string(text))
+	if err != nil {
+		return err
+	}
+	*solver = s
+	return nil
+}
+
+// Iterations returns the number of iterations the last Rank took to converge
+func (graph *Graph) Iterations() (int, error) {
+	if graph.rankState == nil {
+		return 0, ErrNotRanked
+	}
+	return graph.rankState.stats.Iteration, nil
+}
+
+// linearSystem is the pagerank iteration on indexed nodes
+//
+//	x = α Wᵀ x + α v (dᵀ x) + (1 - α) v
+//
+// W holds the normalized edge weights, d marks the dangling nodes and v is the teleport vector.
+// Jacobi and Gauss-Seidel solve (I - α Wᵀ - α v dᵀ) x = (1 - α) v,
+// the ranks are normalized to add up to 1 after every iteration so α = 1 works too
+type linearSystem struct {
+	keys     []string
+	index    map[string]int
+	in       [][]weightedEdge // α * normalized weight of the incoming links, without self links
+	self     []float64        // α * normalized weight of self links
+	dangling []bool
+	v        []float64
+	α        float64
+}
+
+type weightedEdge struct {
+	source int
+	weight float64
+}
+
+// newLinearSystem indexes the finalized and normalized graph, without the teleport vector
+func (graph *Graph) newLinearSystem() *linearSystem {
+	keys := sortedKeys(graph.Nodes)
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+
+	α := graph.Params.α
+	system := &linearSystem{
+		keys:     keys,
+		index:    index,
+		in:       make([][]weightedEdge, len(keys)),
+		self:     make([]float64, len(keys)),
+		dangling: make([]bool, len(keys)),
+		α:        α,
+	}
+	for source, key := range keys {
+		system.dangling[source] = graph.Nodes[key].degree == 0
+		for targetKey, weight := range graph.Edges[key] {
+			target := index[targetKey]
+			if target == source {
+				system.self[source] += α * weight
+				continue
+			}
+			system.in[target] = append(system.in[target], weightedEdge{source: source, weight: α * weight})
+		}
+	}
+	// step adds the incoming rank of a node in this order, sorting by source
+	// keeps the float sums the same from one run to the next
+	for _, edges := range system.in {
+		sort.Slice(edges, func(a, b int) bool { return edges[a].source < edges[b].source })
+	}
+
+	return system
+}
+
+// teleport returns the teleport vector of a personalization vector and its weights
+// without personalization random jumps go to every node
+func (system *linearSystem) teleport(personalization []string, pWeights []float64) []float64 {
+	v := make([]float64, len(system.keys))
+	if len(personalization) == 0 {
+		for i := range v {
+			v[i] = 1 / float64(len(v))
+		}
+		return v
+	}
+	for i, root := range personalization {
+		v[system.index[root]] += pWeights[i]
+	}
+	return v
+}
+
+// danglingRank returns the sum of the ranks of dangling nodes
+func (system *linearSystem) danglingRank(x []float64) float64 {
+	var dangling float64
+	for i, rank := range x {
+		if system.dangling[i] {
+			dangling += rank
+		}
+	}
+	return dangling
+}
+
+// offDiagonal returns the rank node i gets from the other nodes and teleport,
+// and the share of its own rank it keeps (the diagonal of α Wᵀ + α v dᵀ)
+func (system *linearSystem) offDiagonal(i int, x []float64, dangling float64) (float64, float64) {
+	α, v := system.α, system.v[i]
+	diagonal := system.self[i]
+	if system.dangling[i] {
+		diagonal += α * v
+	}
+	rank := α*v*dangling + (1-α)*v - (diagonal-system.self[i])*x[i]
+	for _, edge := range system.in[i] {
+		rank += edge.weight * x[edge.source]
+	}
+	return rank, diagonal
+}
+
+// step runs a single iteration of the solver on x, it returns Δ
+// prev is a buffer for the ranks of the previous iteration
+func (system *linearSystem) step(solver Solver, x, prev []float64) float64 {
+	copy(prev, x)
+	dangling := system.danglingRank(x)
+
+	switch solver {
+	case Jacobi:
+		for i := range x {
+			rank, diagonal := system.offDiagonal(i, prev, dangling)
+			if diagonal < 1 {
+				x[i] = rank / (1 - diagonal)
+			}
+		}
+	case GaussSeidel:
+		for i := range x {
+			rank, diagonal := system.offDiagonal(i, x, dangling)
+			if diagonal >= 1 {
+				continue
+			}
+			next := rank / (1 - diagonal)
+			if system.dangling[i] {
+				dangling += next - x[i]
+			}
+			x[i] = next
+		}
+	default:
+		for i := range x {
+			rank, diagonal := system.offDiagonal(i, prev, dangling)
+			x[i] = rank + diagonal*prev[i]
+		}
+	}
+	normalize(x)
+
+	var Δ float64
+	for i := range x {
+		Δ += math.Abs(x[i] - prev[i])
+	}
+	return Δ
+}
+
+// normalize scales x so it adds up to 1
+func normalize(x []float64) {
+	var sum float64
+	for _, rank := range x {
+		sum += rank
+	}
+	if sum > 0 {
+		for i := range x {
+			x[i] /= sum
+		}
+	}
+}
+
+// extrapolate replaces x with the quadratic extrapolation of the last four iterations
+// it removes the two largest non-principal eigenvectors from x, which is what slows down
+// power iteration when α is close to 1, including the oscillations of cycles
+// see Kamvar et al. Extrapolation Methods for Accelerating PageRank Computations (2003)
+func extrapolate(x, prev, prev2, prev3 []float64) {
+	// y1, y2, y3 are prev2, prev and x minus prev3
+	// find γ1, γ2 minimizing |γ1 y1 + γ2 y2 + y3| with the normal equations
+	var a, b, c, p, q float64
+	for i := range x {
+		y1, y2, y3 := prev2[i]-prev3[i], prev[i]-prev3[i], x[i]-prev3[i]
+		a += y1 * y1
+		b += y1 * y2
+		c += y2 * y2
+		p += y1 * y3
+		q += y2 * y3
+	}
+	det := a*c - b*b
+	if det == 0 || math.IsNaN(det) {
+		return
+	}
+	γ1 := (-p*c + q*b) / det
+	γ2 := (-q*a + p*b) / det
+
+	β0, β1 := γ1+γ2+1, γ2+1
+	for i := range x {
+		x[i] = math.Max(0, β0*prev2[i]+β1*prev[i]+x[i])
+	}
+	normalize(x)
+}
+
+// solve ranks the finalized and normalized graph with a solver other than PowerIteration
+// it starts from the current ranks of the nodes and writes the results back to them
+func (graph *Graph) solve(pWeights []float64) IterationStats {
+	system := graph.newLinearSystem()
+	system.v = system.teleport(graph.Params.Personalization, pWeights)
+	x := make([]float64, len(system.keys))
+	prev := make([]float64, len(x))
+	prev2 := make([]float64, len(x))
+	prev3 := make([]float64, len(x))
+	// with α = 1 there are no random jumps, a Gauss-Seidel sweep that starts with all of the rank
+	// on the seeds would zero them before any rank comes back, so every node starts with some rank
+	for i, key := range system.keys {
+		x[i] = graph.Nodes[key].PRank
+		if x[i] == 0 {
+			x[i] = 1 / float64(len(x))
+		}
+	}
+	normalize(x)
+
+	store := func() {
+		for i, key := range system.keys {
+			graph.Nodes[key].PRank = x[i]
+		}
+	}
+
+	var stats IterationStats
+	Δ := float64(1.0)
+	for iter := 1; Δ > graph.Params.ε; iter++ {
+		if graph.Solver == Extrapolation {
+			copy(prev3, prev2)
+			copy(prev2, prev)
+		}
+		Δ = system.step(graph.Solver, x, prev)
+		if graph.Solver == Extrapolation && Δ > graph.Params.ε && iter%ExtrapolationInterval == 0 {
+			extrapolate(x, prev, prev2, prev3)
+		}
+
+		store()
+		stats = IterationStats{
+			Iteration:       iter,
+			Delta:           Δ,
+			DanglingWeight:  system.α * system.danglingRank(x),
+			NegConsumerRank: graph.negConsumerRank(),
+		}
+		if graph.Observer != nil {
+			graph.Observer.Iteration(graph, stats)
+		}
+	}
+	return stats
+}
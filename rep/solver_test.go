@@ -0,0 +1,144 @@
+package rep
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+var solvers = []Solver{PowerIteration, Jacobi, GaussSeidel, Extrapolation}
+
+// rankWith ranks the links with solver and returns the results and the number of iterations
+func rankWith(solver Solver, α, ε float64, personalized bool, links []exactLink, cached map[string]Ranks) (map[string]Ranks, int) {
+	graph := NewGraph(α, ε, cached[NegConsumerID].PRank)
+	graph.Solver = solver
+	node := func(id string) Node { return NewNode(id, cached[id].PRank, cached[id].NRank) }
+	if personalized {
+		graph.AddPersonalizationNode(node("a"))
+	}
+	for _, link := range links {
+		graph.Link(node(link.source), node(link.target), link.weight)
+	}
+	results := map[string]Ranks{}
+	graph.Rank(func(id string, pRank float64, nRank float64) {
+		results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	iterations, _ := graph.Iterations()
+	return results, iterations
+}
+
+// TestSolversExact compares every solver with the exact ranks, also with α close to 1
+func TestSolversExact(t *testing.T) {
+	ε := 1e-8
+	for _, α := range []float64{0.85, 0.99} {
+		for _, solver := range solvers {
+			for _, test := range exactGraphs {
+				results := map[string]Ranks{}
+				for pass := 0; pass < 2; pass++ {
+					expected := rankExact(t, α, test.personalized, test.links, results)
+					results, _ = rankWith(solver, α, ε, test.personalized, test.links, results)
+
+					// rep keeps a negConsumer with a rank of 0 when it gets no links, exact drops it
+					var worst float64
+					for id, r := range results {
+						if _, ok := expected[id]; !ok {
+							worst = math.Max(worst, r.PRank+r.NRank)
+						}
+					}
+					for id, e := range expected {
+						p, _ := e.PRank.Float64()
+						n, _ := e.NRank.Float64()
+						worst = math.Max(worst, math.Max(math.Abs(results[id].PRank-p), math.Abs(results[id].NRank-n)))
+					}
+					if bound := ε * α / (1 - α); worst > bound {
+						t.Errorf("α %g %s %s pass %d: error %g is larger than %g", α, solver, test.name, pass, worst, bound)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSolverIterations(t *testing.T) {
+	// the slow mode of power iteration is the b -> c -> d cycle, which goes around ~ 1 / (1 - α) times
+	links := exactGraphs[2].links
+	α, ε := 0.99, 1e-8
+
+	_, power := rankWith(PowerIteration, α, ε, true, links, nil)
+	for _, solver := range []Solver{GaussSeidel, Extrapolation} {
+		if _, iterations := rankWith(solver, α, ε, true, links, nil); iterations*10 > power {
+			t.Errorf("%s took %d iterations, power iteration %d", solver, iterations, power)
+		}
+	}
+}
+
+func TestSolverAlphaOne(t *testing.T) {
+	// with α = 1 all of the rank goes back to "a" through the dangling node e
+	// the walks back to "a" have lengths 3 and 4, a graph with only even cycles would oscillate forever
+	links := []exactLink{{"a", "b", 1}, {"a", "c", 1}, {"a", "d", 1}, {"b", "d", 1}, {"c", "d", 1}, {"d", "e", 1}}
+	expected := rankExact(t, 1, true, links, nil)
+	for _, solver := range solvers {
+		results, _ := rankWith(solver, 1, 1e-10, true, links, nil)
+		for id, e := range expected {
+			p, _ := e.PRank.Float64()
+			if math.Abs(results[id].PRank-p) > 1e-8 {
+				t.Errorf("%s: %s expected %f but got %f", solver, id, p, results[id].PRank)
+			}
+		}
+	}
+}
+
+func TestParseSolver(t *testing.T) {
+	for _, solver := range solvers {
+		parsed, err := ParseSolver(solver.String())
+		if err != nil || parsed != solver {
+			t.Errorf("expected %s but got %s %v", solver, parsed, err)
+		}
+	}
+	if _, err := ParseSolver("newton"); err == nil {
+		t.Error("expected an error for an unknown solver")
+	}
+}
+
+func TestSnapshotSolver(t *testing.T) {
+	snapshot := Snapshot{Alpha: 0.85, Epsilon: 0.000001, Solver: GaussSeidel}
+
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"solver": "gauss-seidel"`) {
+		t.Errorf("expected the solver name in %s", buf.String())
+	}
+	decoded, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Graph().Solver != GaussSeidel {
+		t.Errorf("expected %s but got %s", GaussSeidel, decoded.Graph().Solver)
+	}
+
+	buf.Reset()
+	if err := (Snapshot{}).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "solver") {
+		t.Errorf("the default solver should be omitted %s", buf.String())
+	}
+	if _, err := ReadSnapshot(strings.NewReader(`{"solver": "newton"}`)); err == nil {
+		t.Error("expected an error for an unknown solver")
+	}
+}
+
+func TestIterations(t *testing.T) {
+	graph := NewGraph(0.85, 0.000001, 0)
+	if _, err := graph.Iterations(); err != ErrNotRanked {
+		t.Errorf("expected ErrNotRanked, got %v", err)
+	}
+	graph.Link(NewNode("a", 0, 0), NewNode("b", 0, 0), 1)
+	graph.Rank(func(id string, pRank float64, nRank float64) {})
+	if iterations, err := graph.Iterations(); err != nil || iterations == 0 {
+		t.Errorf("expected some iterations, got %d %v", iterations, err)
+	}
+}
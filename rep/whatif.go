@@ -0,0 +1,402 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrRanked is returned by methods that need a graph that has not been ranked yet
+var ErrRanked = errors.New("graph has already been ranked")
+
+// maxPropagationIterations bounds the number of iterations used to propagate a perturbation
+const maxPropagationIterations = 1000
+
+// ChangeType is the kind of change simulated by what-if queries
+type ChangeType int
+
+// Changes to links and to the personalization vector
+const (
+	RemoveLink   ChangeType = iota // remove the link from Source to Target
+	ReweightLink                   // set the weight of the link from Source to Target to Weight (negative for downvotes)
+	FlipLink                       // flip the sign of the link from Source to Target
+	AddSeed                        // add Source to the personalization vector
+	RemoveSeed                     // remove Source from the personalization vector
+)
+
+// Change describes a modification of the graph
+type Change struct {
+	Type   ChangeType
+	Source string
+	Target string
+	Weight float64 // only used by ReweightLink, in the same units as Link
+}
+
+// Impact is the effect of a change on the ranks of a node
+type Impact struct {
+	ID     string
+	Before Ranks
+	After  Ranks
+}
+
+// Estimate returns a first-order estimate of how the ranks of target would change
+// it propagates the change in link weights (or personalization weights) through the converged graph
+// without re-ranking, the ranks of the changed nodes are assumed to stay the same
+// it must be called after Rank, Simulate can be called on the same graph to get the exact impact
+// if the graph keeps its inputs:
+//
+//	graph.RetainInputs = true
+//	graph.Rank(callback)
+//	change := rep.Change{Type: rep.RemoveLink, Source: "a", Target: "b"}
+//	estimate, err := graph.Estimate("b", change) // cheap
+//	impact, err := graph.Simulate("b", change)   // ranks a copy of the graph with the change
+func (graph *Graph) Estimate(target string, change Change) (Impact, error) {
+	if graph.rankState == nil {
+		return Impact{}, ErrNotRanked
+	}
+
+	before, err := graph.rankedResult(target)
+	if err != nil {
+		return Impact{}, err
+	}
+
+	var inject map[string]float64
+	switch change.Type {
+	case RemoveLink, ReweightLink, FlipLink:
+		inject, err = graph.linkPerturbation(change)
+	case AddSeed, RemoveSeed:
+		inject, err = graph.seedPerturbation(change)
+	default:
+		err = fmt.Errorf("unknown change type %d", change.Type)
+	}
+	if err != nil {
+		return Impact{}, err
+	}
+
+	δ := graph.propagate(inject)
+
+	after := before
+	after.PRank = math.Max(0, before.PRank+δ[getKey(target, Positive)])
+	after.NRank = math.Max(0, before.NRank+δ[getKey(target, Negative)])
+
+	return Impact{ID: target, Before: before, After: after}, nil
+}
+
+// Simulate applies the change to a copy of the graph's inputs and ranks it, the graph itself is not modified
+// it can be called before Rank, the graph is then also ranked without the change to get the ranks before it,
+// or after Rank if RetainInputs was set, otherwise it returns ErrRanked
+func (graph *Graph) Simulate(target string, change Change) (Impact, error) {
+	inputs := graph
+	if graph.rankState != nil {
+		if inputs = graph.rankState.inputs; inputs == nil {
+			return Impact{}, ErrRanked
+		}
+	}
+
+	rank := func(g *Graph) map[string]Ranks {
+		g.Observer = nil
+		results := map[string]Ranks{}
+		g.Rank(func(id string, pRank float64, nRank float64) {
+			results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+		})
+		return results
+	}
+
+	changed := inputs.Clone()
+	if err := changed.applyChange(change); err != nil {
+		return Impact{}, err
+	}
+
+	var before Ranks
+	if graph.rankState != nil {
+		var err error
+		if before, err = graph.rankedResult(target); err != nil {
+			return Impact{}, err
+		}
+	} else {
+		var ok bool
+		if before, ok = rank(graph.Clone())[target]; !ok {
+			return Impact{}, fmt.Errorf("node %s not found", target)
+		}
+	}
+	after := rank(changed)[target]
+	after.ID = target
+
+	return Impact{ID: target, Before: before, After: after}, nil
+}
+
+// rankedResult returns the current ranks of a node in a ranked graph
+func (graph *Graph) rankedResult(id string) (Ranks, error) {
+	posNode, isPos := graph.Nodes[id]
+	negNode, isNeg := graph.NegNodes[getKey(id, Negative)]
+	if !isPos && !isNeg {
+		return Ranks{}, fmt.Errorf("node %s not found", id)
+	}
+	result := Ranks{ID: id}
+	if isPos {
+		result.PRank = posNode.PRank
+	}
+	if isNeg {
+		result.NRank = negNode.PRank
+	}
+	return result, nil
+}
+
+// linkKeys returns the key of the existing link from source to target (if any)
+// and the key a link with the given weight would use
+func linkKeys(row map[string]float64, target string, change Change) (oldKey string, newKey string) {
+	posKey := getKey(target, Positive)
+	negKey := getKey(target, Negative)
+	if _, ok := row[posKey]; ok {
+		oldKey = posKey
+	}
+	if _, ok := row[negKey]; ok {
+		oldKey = negKey
+	}
+
+	switch change.Type {
+	case FlipLink:
+		if newKey = posKey; oldKey == posKey {
+			newKey = negKey
+		}
+	case ReweightLink:
+		if newKey = posKey; change.Weight < 0 {
+			newKey = negKey
+		}
+	}
+	return oldKey, newKey
+}
+
+// linkPerturbation returns the change in rank sent by the source of a link in one iteration
+func (graph *Graph) linkPerturbation(change Change) (map[string]float64, error) {
+	sourceNode, ok := graph.Nodes[change.Source]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found", change.Source)
+	}
+
+	α := graph.Params.α
+	row := graph.Edges[change.Source]
+	oldKey, newKey := linkKeys(row, change.Target, change)
+	if oldKey == "" && change.Type != ReweightLink {
+		return nil, fmt.Errorf("link from %s to %s not found", change.Source, change.Target)
+	}
+
+	// edges are normalized, so we recover the raw weights from the degree
+	// the negConsumer link is proportional to the rest of the outgoing links so its share doesn't change
+	degree := sourceNode.degree
+	negShare := row[graph.NegConsumer.ID]
+	rawDegree := degree * (1 - negShare)
+	rawOld := row[oldKey] * degree
+
+	var rawNew float64
+	switch change.Type {
+	case FlipLink:
+		rawNew = rawOld
+	case ReweightLink:
+		rawNew = math.Abs(change.Weight)
+	}
+	newRawDegree := rawDegree - rawOld + rawNew
+
+	newRow := map[string]float64{}
+	if newRawDegree > 0 {
+		scale := (1 - negShare) / newRawDegree
+		for key, weight := range row {
+			if key == oldKey || key == graph.NegConsumer.ID {
+				continue
+			}
+			newRow[key] = weight * degree * scale
+		}
+		if rawNew > 0 {
+			newRow[newKey] += rawNew * scale
+		}
+		if negShare > 0 {
+			newRow[graph.NegConsumer.ID] = negShare
+		}
+	}
+
+	rank := sourceNode.PRank
+	inject := map[string]float64{}
+	for key, weight := range row {
+		inject[key] -= α * rank * weight
+	}
+	for key, weight := range newRow {
+		inject[key] += α * rank * weight
+	}
+
+	// nodes without outgoing links send their rank to the teleport vector
+	wasDangling, isDangling := len(row) == 0, len(newRow) == 0
+	if wasDangling != isDangling {
+		sign := float64(1)
+		if wasDangling {
+			sign = -1
+		}
+		for key, share := range graph.teleportVector(graph.Params.Personalization) {
+			inject[key] += sign * α * rank * share
+		}
+	}
+	return inject, nil
+}
+
+// seedPerturbation returns the change in teleported rank in one iteration
+func (graph *Graph) seedPerturbation(change Change) (map[string]float64, error) {
+	pVector := graph.Params.Personalization
+	var next []string
+
+	switch change.Type {
+	case AddSeed:
+		if _, ok := graph.Nodes[change.Source]; !ok {
+			return nil, fmt.Errorf("node %s not found", change.Source)
+		}
+		next = append(append([]string{}, pVector...), change.Source)
+	case RemoveSeed:
+		next = removeString(pVector, change.Source)
+		if len(next) == len(pVector) {
+			return nil, fmt.Errorf("node %s is not a personalization node", change.Source)
+		}
+	}
+
+	teleport := 1 - graph.Params.α + graph.rankState.stats.DanglingWeight
+	inject := map[string]float64{}
+	for key, share := range graph.teleportVector(pVector) {
+		inject[key] -= teleport * share
+	}
+	for key, share := range graph.teleportVector(next) {
+		inject[key] += teleport * share
+	}
+	return inject, nil
+}
+
+// teleportVector returns the share of random jumps and dangling rank each node gets
+func (graph *Graph) teleportVector(pVector []string) map[string]float64 {
+	vector := map[string]float64{}
+	if len(pVector) == 0 {
+		keys := graph.rankState.keys
+		for _, key := range keys {
+			vector[key] = 1 / float64(len(keys))
+		}
+		return vector
+	}
+	for i, weight := range graph.personalizationWeights(pVector) {
+		vector[pVector[i]] += weight
+	}
+	return vector
+}
+
+// propagate sums the effect of a one-iteration perturbation over all following iterations
+func (graph *Graph) propagate(inject map[string]float64) map[string]float64 {
+	α := graph.Params.α
+	ε := graph.Params.ε
+	teleport := graph.teleportVector(graph.Params.Personalization)
+
+	total := map[string]float64{}
+	term := inject
+	for i := 0; i < maxPropagationIterations; i++ {
+		var norm float64
+		for key, value := range term {
+			total[key] += value
+			norm += math.Abs(value)
+		}
+		if norm <= ε {
+			break
+		}
+
+		next := map[string]float64{}
+		var dangling float64
+		for key, value := range term {
+			row := graph.Edges[key]
+			if len(row) == 0 {
+				dangling += value
+				continue
+			}
+			for target, weight := range row {
+				next[target] += α * value * weight
+			}
+		}
+		for key, share := range teleport {
+			next[key] += α * dangling * share
+		}
+		term = next
+	}
+	return total
+}
+
+// applyChange modifies an unranked graph
+func (graph *Graph) applyChange(change Change) error {
+	switch change.Type {
+	case AddSeed:
+		node, ok := graph.Nodes[change.Source]
+		if !ok {
+			return fmt.Errorf("node %s not found", change.Source)
+		}
+		graph.Params.Personalization = append(graph.Params.Personalization, node.ID)
+		return nil
+	case RemoveSeed:
+		next := removeString(graph.Params.Personalization, change.Source)
+		if len(next) == len(graph.Params.Personalization) {
+			return fmt.Errorf("node %s is not a personalization node", change.Source)
+		}
+		graph.Params.Personalization = next
+		return nil
+	case RemoveLink, ReweightLink, FlipLink:
+	default:
+		return fmt.Errorf("unknown change type %d", change.Type)
+	}
+
+	sourceNode, ok := graph.Nodes[change.Source]
+	if !ok {
+		return fmt.Errorf("node %s not found", change.Source)
+	}
+
+	row := graph.Edges[change.Source]
+	oldKey, newKey := linkKeys(row, change.Target, change)
+	if oldKey == "" && change.Type != ReweightLink {
+		return fmt.Errorf("link from %s to %s not found", change.Source, change.Target)
+	}
+
+	var rawOld float64
+	if oldKey != "" {
+		rawOld = row[oldKey]
+		graph.removeEdge(change.Source, oldKey)
+		sourceNode.degree -= rawOld
+	}
+
+	var rawNew float64
+	switch change.Type {
+	case FlipLink:
+		rawNew = rawOld
+	case ReweightLink:
+		rawNew = math.Abs(change.Weight)
+	}
+	if rawNew == 0 {
+		return nil
+	}
+
+	nodeType := Positive
+	if newKey != getKey(change.Target, Positive) {
+		nodeType = Negative
+	}
+	// keep the cached rank if the node already exists
+	var cachedRank float64
+	if node, ok := graph.Nodes[newKey]; ok {
+		cachedRank = node.PRank
+	}
+	graph.initNode(newKey, Node{ID: change.Target, PRank: cachedRank, NRank: cachedRank}, nodeType)
+
+	if _, ok := graph.Edges[change.Source]; ok == false {
+		graph.Edges[change.Source] = map[string]float64{}
+	}
+	graph.Edges[change.Source][newKey] += rawNew
+	sourceNode.degree += rawNew
+	return nil
+}
+
+// removeString returns a copy of s without any occurrence of str
+func removeString(s []string, str string) []string {
+	result := make([]string, 0, len(s))
+	for _, v := range s {
+		if v != str {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package rep
+
+import (
+	"math"
+	"testing"
+)
+
+func whatIfLinks(graph *Graph, n map[string]Node) {
+	graph.Link(n["a"], n["b"], 1.0)
+	graph.Link(n["a"], n["c"], 2.0)
+	graph.Link(n["b"], n["c"], 1.0)
+	graph.Link(n["c"], n["d"], 1.0)
+	graph.Link(n["b"], n["d"], -1.0)
+	graph.Link(n["d"], n["e"], 1.0)
+	graph.Link(n["c"], n["e"], 1.0)
+}
+
+// unrankedGraph returns a graph built with the cached ranks of a previous pass
+func unrankedGraph(results map[string]Ranks, links func(graph *Graph, nodes map[string]Node)) *Graph {
+	graph := NewGraph(0.85, 0.000001, results[NegConsumerID].PRank)
+	nodes := map[string]Node{}
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		nodes[id] = NewNode(id, results[id].PRank, results[id].NRank)
+	}
+	graph.AddPersonalizationNode(nodes["a"])
+	links(graph, nodes)
+	return graph
+}
+
+func TestEstimate(t *testing.T) {
+	graph, results := rankTwice(whatIfLinks)
+
+	tests := []struct {
+		name      string
+		target    string
+		change    Change
+		tolerance float64 // relative error of the estimated change in rank
+	}{
+		{"reweight", "c", Change{Type: ReweightLink, Source: "b", Target: "c", Weight: 1.1}, 0.05},
+		{"remove", "e", Change{Type: RemoveLink, Source: "c", Target: "e"}, 0.3},
+		{"flip", "d", Change{Type: FlipLink, Source: "b", Target: "d"}, 0.3},
+		{"new negative link", "e", Change{Type: ReweightLink, Source: "a", Target: "e", Weight: -0.5}, 0.3},
+		{"add seed", "d", Change{Type: AddSeed, Source: "c"}, 0.5},
+	}
+
+	for _, tt := range tests {
+		estimate, err := graph.Estimate(tt.target, tt.change)
+		if err != nil {
+			t.Fatal(tt.name, err)
+		}
+		if estimate.Before != results[tt.target] {
+			t.Errorf("%s: before should match the results %v %v", tt.name, estimate.Before, results[tt.target])
+		}
+
+		exact, err := unrankedGraph(results, whatIfLinks).Simulate(tt.target, tt.change)
+		if err != nil {
+			t.Fatal(tt.name, err)
+		}
+
+		check := func(kind string, estimated, before, after float64) {
+			exactΔ := after - before
+			if math.Abs(exactΔ) < 1e-6 {
+				return
+			}
+			if math.Abs(estimated-after) > tt.tolerance*math.Abs(exactΔ) {
+				t.Errorf("%s: estimated %s %f too far from exact %f (before %f)", tt.name, kind, estimated, after, before)
+			}
+		}
+		check("pRank", estimate.After.PRank, exact.Before.PRank, exact.After.PRank)
+		check("nRank", estimate.After.NRank, exact.Before.NRank, exact.After.NRank)
+	}
+}
+
+func TestEstimateErrors(t *testing.T) {
+	graph := NewGraph(0.85, 0.000001, 0)
+	a, b := NewNode("a", 0, 0), NewNode("b", 0, 0)
+	graph.AddPersonalizationNode(a)
+	graph.Link(a, b, 1.0)
+
+	if _, err := graph.Estimate("b", Change{Type: RemoveLink, Source: "a", Target: "b"}); err != ErrNotRanked {
+		t.Errorf("expected ErrNotRanked, got %v", err)
+	}
+
+	graph.Rank(func(string, float64, float64) {})
+
+	if _, err := graph.Estimate("b", Change{Type: RemoveLink, Source: "b", Target: "a"}); err == nil {
+		t.Errorf("expected an error for a missing link")
+	}
+	if _, err := graph.Estimate("b", Change{Type: RemoveSeed, Source: "b"}); err == nil {
+		t.Errorf("expected an error for a missing seed")
+	}
+	if _, err := graph.Simulate("b", Change{Type: RemoveLink, Source: "a", Target: "b"}); err != ErrRanked {
+		t.Errorf("expected ErrRanked without RetainInputs, got %v", err)
+	}
+}
+
+func TestSimulateDoesNotModifyGraph(t *testing.T) {
+	graph := unrankedGraph(map[string]Ranks{}, whatIfLinks)
+	degree := graph.Nodes["c"].degree
+	edges := len(graph.Edges["c"])
+
+	impact, err := graph.Simulate("e", Change{Type: RemoveLink, Source: "c", Target: "e"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if impact.After.PRank >= impact.Before.PRank {
+		t.Errorf("removing a link to e should lower its rank %v", impact)
+	}
+	if graph.rankState != nil || graph.Nodes["c"].degree != degree || len(graph.Edges["c"]) != edges {
+		t.Errorf("simulate should not modify the graph")
+	}
+
+	// a ranked graph simulates the change from the inputs it was ranked with
+	graph.RetainInputs = true
+	results := map[string]Ranks{}
+	graph.Rank(func(id string, pRank float64, nRank float64) {
+		results[id] = Ranks{ID: id, PRank: pRank, NRank: nRank}
+	})
+	ranked, err := graph.Simulate("e", Change{Type: RemoveLink, Source: "c", Target: "e"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranked.Before != results["e"] {
+		t.Errorf("before should be the ranked result %v %v", ranked.Before, results["e"])
+	}
+	if math.Abs(ranked.After.PRank-impact.After.PRank) > 1e-9 || math.Abs(ranked.After.NRank-impact.After.NRank) > 1e-9 {
+		t.Errorf("expected the same impact before and after Rank %v %v", ranked, impact)
+	}
+	if _, err := graph.Simulate("e", Change{Type: RemoveLink, Source: "b", Target: "a"}); err == nil {
+		t.Errorf("expected an error for a missing link")
+	}
+}
@@ -0,0 +1,13 @@
+#!/usr/bin/env bash
+
+# generates the go code of the proto files in proto/ with buf,
+# protoc-gen-gocosmos (github.com/regen-network/cosmos-proto) and
+# protoc-gen-grpc-gateway (github.com/grpc-ecosystem/grpc-gateway v1) must be installed
+
+set -eo pipefail
+
+buf generate --template buf.gen.yaml proto
+
+# move the generated files to the right places
+cp -r github.com/relevant-community/reputation/* ./
+rm -rf github.com
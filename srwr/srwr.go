@@ -0,0 +1,221 @@
+// Package srwr ranks signed graphs with a signed random walk with restart
+// (Jung, Jin, Sael and Kang, Personalized Ranking in Signed Networks using Signed Random Walk with Restart, 2016)
+// it takes the same links and personalization nodes as rep.Graph and returns results in the same format.
+//
+// the surfer carries a sign: it starts positive at the personalization nodes, a negative link flips it and
+// a positive link keeps it. a node's pRank is the probability of meeting a positive surfer and nRank a negative one,
+// so unlike the negConsumer in rep distrust propagates ("the enemy of my friend is my enemy").
+// what happens to a negative surfer is set by the balance rule of the graph
+package srwr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/relevant-community/reputation/rep"
+)
+
+var _ rep.Linker = (*Graph)(nil)
+
+// Balance is the balance theory rule for negative surfers, both values are probabilities
+type Balance struct {
+	// EnemyOfEnemy is the probability that a negative surfer becomes positive on a negative link
+	// "the enemy of my enemy is my friend", β in the paper
+	EnemyOfEnemy float64
+	// FriendOfEnemy is the probability that a negative surfer stays negative on a positive link
+	// "the friend of my enemy is my enemy", γ in the paper
+	FriendOfEnemy float64
+}
+
+// FullBalance is structural balance theory (Heider): the enemy of my enemy is my friend
+// and the friend of my enemy is my enemy, this is the default
+var FullBalance = Balance{EnemyOfEnemy: 1, FriendOfEnemy: 1}
+
+// WeakBalance is weak structural balance (Davis): the enemy of my enemy is not my friend,
+// negative surfers stay negative
+var WeakBalance = Balance{EnemyOfEnemy: 0, FriendOfEnemy: 1}
+
+// Option configures a graph
+type Option func(graph *Graph)
+
+// WithBalance sets the balance rule of a graph, FullBalance by default
+func WithBalance(balance Balance) Option {
+	for _, p := range []float64{balance.EnemyOfEnemy, balance.FriendOfEnemy} {
+		if !(p >= 0 && p <= 1) {
+			panic(fmt.Sprintf("srwr: balance probabilities must be between 0 and 1, got %+v", balance))
+		}
+	}
+	return func(graph *Graph) {
+		graph.balance = balance
+	}
+}
+
+// Graph holds the signed links between nodes
+type Graph struct {
+	α, ε            float64
+	balance         Balance
+	personalization []string
+	nodes           map[string]bool
+	edges           map[string]map[string]float64 // sum of positive minus negative links
+}
+
+// NewGraph returns an empty graph
+// α is the probability of not restarting and ε the convergence criteria, like in rep
+func NewGraph(α, ε float64, options ...Option) *Graph {
+	graph := &Graph{
+		α:               α,
+		ε:               ε,
+		balance:         FullBalance,
+		personalization: []string{},
+		nodes:           map[string]bool{},
+		edges:           map[string]map[string]float64{},
+	}
+	for _, option := range options {
+		option(graph)
+	}
+	return graph
+}
+
+// FromSnapshot builds a graph with the params, personalization and links of a rep snapshot
+// cached ranks are not used
+func FromSnapshot(snapshot rep.Snapshot, options ...Option) *Graph {
+	graph := NewGraph(snapshot.Alpha, snapshot.Epsilon, options...)
+	snapshot.Load(graph)
+	return graph
+}
+
+// AddPersonalizationNode adds a node the surfer restarts from
+// personalization nodes are weighted by degree like in rep
+func (graph *Graph) AddPersonalizationNode(pNode rep.Node) {
+	graph.personalization = append(graph.personalization, pNode.ID)
+	graph.nodes[pNode.ID] = true
+}
+
+// Link creates a weighted edge between a source-target node pair, negative weights are negative links
+// opposite links between the same nodes cancel out like in rep, the cached ranks of the nodes are ignored
+func (graph *Graph) Link(source, target rep.Node, weight float64) {
+	graph.nodes[source.ID] = true
+	graph.nodes[target.ID] = true
+	if _, ok := graph.edges[source.ID]; !ok {
+		graph.edges[source.ID] = map[string]float64{}
+	}
+	graph.edges[source.ID][target.ID] += weight
+}
+
+// signedEdge is a normalized link
+type signedEdge struct {
+	target   int
+	weight   float64
+	negative bool
+}
+
+// Rank computes the positive and negative ranks of every node and calls callback for each one
+// the surfer restarts as a positive surfer with probability 1 - α, and from dangling nodes
+// the ranks of all nodes add up to 1
+func (graph *Graph) Rank(callback func(id string, pRank float64, nRank float64)) {
+	keys := make([]string, 0, len(graph.nodes))
+	for key := range graph.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	edges, degrees := graph.normalizedEdges(keys, index)
+	v := graph.restartVector(keys, index, degrees)
+
+	α, β, γ := graph.α, graph.balance.EnemyOfEnemy, graph.balance.FriendOfEnemy
+	pos, neg := append([]float64{}, v...), make([]float64, len(keys))
+	nextPos, nextNeg := make([]float64, len(keys)), make([]float64, len(keys))
+	for Δ := 1.0; Δ > graph.ε; {
+		for i := range keys {
+			nextPos[i], nextNeg[i] = 0, 0
+		}
+		var dangling float64
+		for i, out := range edges {
+			if len(out) == 0 {
+				dangling += pos[i] + neg[i]
+				continue
+			}
+			for _, edge := range out {
+				p, n := α*edge.weight*pos[i], α*edge.weight*neg[i]
+				if edge.negative {
+					nextNeg[edge.target] += p + (1-β)*n
+					nextPos[edge.target] += β * n
+				} else {
+					nextPos[edge.target] += p + (1-γ)*n
+					nextNeg[edge.target] += γ * n
+				}
+			}
+		}
+		for i := range keys {
+			nextPos[i] += (1 - α + α*dangling) * v[i]
+		}
+
+		Δ = 0
+		for i := range keys {
+			Δ += math.Abs(nextPos[i]-pos[i]) + math.Abs(nextNeg[i]-neg[i])
+		}
+		pos, nextPos = nextPos, pos
+		neg, nextNeg = nextNeg, neg
+	}
+
+	for i, key := range keys {
+		callback(key, pos[i], neg[i])
+	}
+}
+
+// normalizedEdges returns the outgoing links of every node, indexed like keys, and the degree of every node
+func (graph *Graph) normalizedEdges(keys []string, index map[string]int) ([][]signedEdge, []float64) {
+	edges := make([][]signedEdge, len(keys))
+	degrees := make([]float64, len(keys))
+	for i, key := range keys {
+		for target, weight := range graph.edges[key] {
+			if weight == 0 {
+				continue
+			}
+			edges[i] = append(edges[i], signedEdge{target: index[target], weight: math.Abs(weight), negative: weight < 0})
+		}
+		// sorted by target so the degree, a float sum, doesn't depend on map order
+		sort.Slice(edges[i], func(a, b int) bool { return edges[i][a].target < edges[i][b].target })
+
+		for _, edge := range edges[i] {
+			degrees[i] += edge.weight
+		}
+		for j := range edges[i] {
+			edges[i][j].weight /= degrees[i]
+		}
+	}
+	return edges, degrees
+}
+
+// restartVector returns the share of the restarts that goes to each node
+// personalization nodes are weighted by degree, 1 if they have no outgoing links, like in rep
+func (graph *Graph) restartVector(keys []string, index map[string]int, degrees []float64) []float64 {
+	v := make([]float64, len(keys))
+	if len(graph.personalization) == 0 {
+		for i := range v {
+			v[i] = 1 / float64(len(v))
+		}
+		return v
+	}
+	var sum float64
+	for _, id := range graph.personalization {
+		d := 1.0
+		if degree := degrees[index[id]]; degree > 0 {
+			d = degree
+		}
+		v[index[id]] += d
+		sum += d
+	}
+	for i := range v {
+		v[i] /= sum
+	}
+	return v
+}
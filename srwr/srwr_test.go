@@ -0,0 +1,93 @@
+package srwr
+
+import (
+	"math"
+	"testing"
+
+	"github.com/relevant-community/reputation/internal/ranktest"
+	"github.com/relevant-community/reputation/rep"
+)
+
+var (
+	node        = ranktest.Node
+	rank        = ranktest.Rank
+	expectRanks = ranktest.ExpectRanks
+)
+
+// chain is a -> b -> c -> d -> e with negative links from b and d, a is the seed
+func chain(options ...Option) *Graph {
+	graph := NewGraph(0.5, 1e-12, options...)
+	graph.AddPersonalizationNode(node("a"))
+	graph.Link(node("a"), node("b"), 1)
+	graph.Link(node("b"), node("c"), -1)
+	graph.Link(node("c"), node("d"), 1)
+	graph.Link(node("d"), node("e"), -1)
+	return graph
+}
+
+func TestEmpty(t *testing.T) {
+	ranktest.ExpectEmpty(t, NewGraph(0.85, 1e-12))
+}
+
+func TestFullBalance(t *testing.T) {
+	// c is an enemy of b, d a friend of c and e an enemy of d: b+, c-, d-, e+
+	// the rank halves along the chain and e is dangling: a = 1/2 + 1/32 a => a = 16/31
+	expectRanks(t, rank(chain()), map[string][2]float64{
+		"a": {16.0 / 31, 0}, "b": {8.0 / 31, 0}, "c": {0, 4.0 / 31}, "d": {0, 2.0 / 31}, "e": {1.0 / 31, 0},
+	})
+}
+
+func TestWeakBalance(t *testing.T) {
+	// the enemy of my enemy is not my friend, e is negative
+	expectRanks(t, rank(chain(WithBalance(WeakBalance))), map[string][2]float64{
+		"a": {16.0 / 31, 0}, "b": {8.0 / 31, 0}, "c": {0, 4.0 / 31}, "d": {0, 2.0 / 31}, "e": {0, 1.0 / 31},
+	})
+}
+
+func TestFriendOfEnemy(t *testing.T) {
+	// half of the negative surfers become positive on the c -> d link
+	results := rank(chain(WithBalance(Balance{EnemyOfEnemy: 1, FriendOfEnemy: 0.5})))
+	if d := results["d"]; math.Abs(d.PRank-d.NRank) > 1e-9 || d.PRank == 0 {
+		t.Errorf("d should be split between positive and negative, got %v", d)
+	}
+
+	var sum float64
+	for _, r := range results {
+		sum += r.PRank + r.NRank
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("ranks should add up to 1, got %f", sum)
+	}
+}
+
+func TestInvalidBalance(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	WithBalance(Balance{EnemyOfEnemy: 2})
+}
+
+func TestPositiveLinksMatchRep(t *testing.T) {
+	// without negative links the signed walk is personalized pagerank
+	ranktest.ExpectRepRanks(t, NewGraph(0.85, 1e-12), rep.NewGraph(0.85, 1e-12, 0), []string{"a", "f"}, ranktest.PositiveLinks)
+}
+
+func TestFromSnapshot(t *testing.T) {
+	snapshot := rep.Snapshot{
+		Alpha:           0.5,
+		Epsilon:         1e-12,
+		Personalization: []string{"a"},
+		Links: []rep.SnapshotLink{
+			{Source: "a", Target: "b", Weight: 1},
+			{Source: "b", Target: "c", Weight: -1},
+			{Source: "c", Target: "d", Weight: 1},
+			{Source: "d", Target: "e", Weight: -1},
+		},
+	}
+	// same as TestWeakBalance
+	expectRanks(t, rank(FromSnapshot(snapshot, WithBalance(WeakBalance))), map[string][2]float64{
+		"a": {16.0 / 31, 0}, "b": {8.0 / 31, 0}, "c": {0, 4.0 / 31}, "d": {0, 2.0 / 31}, "e": {0, 1.0 / 31},
+	})
+}
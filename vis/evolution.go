@@ -0,0 +1,124 @@
+package vis
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	rep "github.com/relevant-community/reputation/rep"
+)
+
+// Step holds the ranks of all nodes after a single iteration of a rank pass
+// iteration 0 holds the start scores of the pass
+type Step struct {
+	Pass      int
+	Iteration int
+	Delta     float64
+	Ranks     map[string]rep.Ranks
+}
+
+// Recorder is a rep.Observer that records the ranks of all nodes after every iteration
+// every call to Rank is recorded as a new pass
+type Recorder struct {
+	Steps []Step
+	pass  int
+}
+
+// Start starts recording a new pass
+func (recorder *Recorder) Start(graph *rep.Graph) {
+	recorder.pass++
+	recorder.record(graph, 0, 0)
+}
+
+// Iteration records the ranks after an iteration
+func (recorder *Recorder) Iteration(graph *rep.Graph, stats rep.IterationStats) {
+	recorder.record(graph, stats.Iteration, stats.Delta)
+}
+
+// Complete is a noop, the last iteration is already recorded
+func (recorder *Recorder) Complete(graph *rep.Graph, stats rep.IterationStats) {}
+
+func (recorder *Recorder) record(graph *rep.Graph, iteration int, delta float64) {
+	ranks := map[string]rep.Ranks{}
+	for key, node := range graph.Nodes {
+		if negNode, ok := graph.NegNodes[key]; ok {
+			result := ranks[negNode.ID]
+			result.ID = negNode.ID
+			result.NRank = node.PRank
+			ranks[negNode.ID] = result
+			continue
+		}
+		result := ranks[key]
+		result.ID = key
+		result.PRank = node.PRank
+		ranks[key] = result
+	}
+	recorder.Steps = append(recorder.Steps, Step{
+		Pass:      recorder.pass,
+		Iteration: iteration,
+		Delta:     delta,
+		Ranks:     ranks,
+	})
+}
+
+// NewEvolutionChart draws the net rank of every node over the recorded iterations and passes
+// ids limits the chart to specific nodes, all recorded nodes are drawn if no ids are passed
+func NewEvolutionChart(steps []Step, title string, ids ...string) *charts.Line {
+	if len(ids) == 0 {
+		ids = stepIDs(steps)
+	}
+
+	labels := make([]string, len(steps))
+	var passStarts []opts.MarkLineNameXAxisItem
+	for i, step := range steps {
+		labels[i] = fmt.Sprintf("pass %d: %d", step.Pass, step.Iteration)
+		if step.Iteration == 0 && step.Pass > 1 {
+			passStarts = append(passStarts, opts.MarkLineNameXAxisItem{
+				Name:  fmt.Sprintf("pass %d", step.Pass),
+				XAxis: labels[i],
+			})
+		}
+	}
+
+	chart := charts.NewLine()
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: title}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true, Trigger: "axis"}),
+		charts.WithLegendOpts(opts.Legend{Show: true, Top: "bottom"}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "iteration"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "rank"}),
+		charts.WithDataZoomOpts(opts.DataZoom{Type: "slider"}),
+	)
+	chart.SetXAxis(labels)
+
+	for i, id := range ids {
+		data := make([]opts.LineData, len(steps))
+		for j, step := range steps {
+			result := step.Ranks[id]
+			data[j] = opts.LineData{Value: result.PRank - result.NRank}
+		}
+		var options []charts.SeriesOpts
+		if i == 0 && len(passStarts) > 0 {
+			options = append(options, charts.WithMarkLineNameXAxisItemOpts(passStarts...))
+		}
+		chart.AddSeries(id, data, options...)
+	}
+	return chart
+}
+
+// stepIDs returns the sorted ids of all the nodes in the steps
+func stepIDs(steps []Step) []string {
+	set := map[string]bool{}
+	for _, step := range steps {
+		for id := range step.Ranks {
+			set[id] = true
+		}
+	}
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,84 @@
+package vis
+
+import (
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	rep "github.com/relevant-community/reputation/rep"
+)
+
+// NewPathsChart draws only the nodes and links that are part of the given trust paths
+// links carry the flow of the paths going through them
+func NewPathsChart(graph *rep.Graph, results map[string]rep.Ranks, paths []rep.Path, title string) *charts.Graph {
+	data := PathsData(graph, results, paths)
+
+	chart := charts.NewGraph()
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: title,
+		}))
+
+	chart.AddSeries("paths", data.Nodes, data.Links).
+		SetSeriesOptions(
+			charts.WithGraphChartOpts(opts.GraphChart{
+				Categories: []*opts.GraphCategory{
+					{Name: "Personalization"},
+					{Name: "Negative"},
+					{Name: "Positive"},
+					{Name: "Neg Consumer"},
+				},
+				Force:              &opts.GraphForce{Repulsion: 2000},
+				Layout:             "force",
+				Roam:               true,
+				FocusNodeAdjacency: true,
+			}),
+			charts.WithLabelOpts(opts.Label{Show: true, Position: "right", Color: "black"}),
+		)
+	return chart
+}
+
+// PathsData returns the echarts nodes and links of the trust paths
+// link values are the summed flow of all paths using the link, negative links have a negative flow
+func PathsData(graph *rep.Graph, results map[string]rep.Ranks, paths []rep.Path) Data {
+	all := GraphData(graph, results)
+
+	inPath := map[string]bool{}
+	flows := map[pathLink]float64{}
+	var order []pathLink
+	for _, path := range paths {
+		inPath[path.Seed] = true
+		for _, hop := range path.Hops {
+			inPath[hop.Target] = true
+			key := pathLink{source: hop.Source, target: hop.Target, negative: hop.Negative}
+			if _, ok := flows[key]; !ok {
+				order = append(order, key)
+			}
+			flow := path.Flow
+			if hop.Negative {
+				flow = -flow
+			}
+			flows[key] += flow
+		}
+	}
+
+	var data Data
+	for _, node := range all.Nodes {
+		if inPath[node.Name] {
+			data.Nodes = append(data.Nodes, node)
+		}
+	}
+	for _, key := range order {
+		data.Links = append(data.Links, opts.GraphLink{
+			Source: key.source,
+			Target: key.target,
+			Value:  float32(flows[key]),
+		})
+	}
+	return data
+}
+
+// pathLink identifies a link used by trust paths
+type pathLink struct {
+	source   string
+	target   string
+	negative bool
+}
@@ -0,0 +1,202 @@
+// Package vis renders reputation graphs and their rank results as go-echarts charts
+package vis
+
+import (
+	"io"
+	"math"
+	"sort"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	rep "github.com/relevant-community/reputation/rep"
+)
+
+// node categories, the order matches the categories in the chart options
+const (
+	personalizationCategory = iota
+	negativeCategory
+	positiveCategory
+	negConsumerCategory
+)
+
+// maxSymbolSize is the size of the highest ranking node
+const maxSymbolSize = 100
+
+// Data holds echarts nodes and links
+type Data struct {
+	Nodes []opts.GraphNode
+	Links []opts.GraphLink
+}
+
+// NewGraphChart turns a graph and its rank results into an echarts graph
+// results are keyed by node id and can be nil, nodes without results are drawn with a rank of 0
+func NewGraphChart(graph *rep.Graph, results map[string]rep.Ranks, title string) *charts.Graph {
+	data := GraphData(graph, results)
+
+	chart := charts.NewGraph()
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: title,
+		}))
+
+	chart.AddSeries("graph", data.Nodes, data.Links).
+		SetSeriesOptions(
+			charts.WithGraphChartOpts(opts.GraphChart{
+				Categories: []*opts.GraphCategory{
+					{Name: "Personalization"},
+					{Name: "Negative"},
+					{Name: "Positive"},
+					{Name: "Neg Consumer"},
+				},
+				Force:              &opts.GraphForce{Repulsion: 2000},
+				Layout:             "force",
+				Roam:               true,
+				FocusNodeAdjacency: true,
+			}),
+			charts.WithLabelOpts(opts.Label{Show: true, Position: "right", Color: "black"}),
+
+			charts.WithEmphasisOpts(opts.Emphasis{
+				Label: &opts.Label{
+					Formatter: "rank: {c}",
+					Show:      true,
+					Color:     "black",
+				},
+			}),
+		)
+	return chart
+}
+
+// GraphData converts graph nodes and signed edges into echarts nodes and links
+// nodes are sized relative to the node with the highest absolute net rank
+func GraphData(graph *rep.Graph, results map[string]rep.Ranks) Data {
+	ids := nodeIDs(graph, results)
+
+	var maxRank float64
+	for _, id := range ids {
+		maxRank = math.Max(maxRank, math.Abs(results[id].PRank-results[id].NRank))
+	}
+
+	var nodes []opts.GraphNode
+	for _, id := range ids {
+		rank := results[id].PRank - results[id].NRank
+
+		var symbol string
+		var category int
+		switch true {
+		case id == graph.NegConsumer.ID:
+			symbol = "diamond"
+			category = negConsumerCategory
+		case contains(graph.Params.Personalization, id):
+			symbol = "triangle"
+			category = personalizationCategory
+		default:
+			symbol = "circle"
+			category = positiveCategory
+		}
+
+		color := "#39FF14"
+		if rank < 0 {
+			color = "pink"
+			category = negativeCategory
+		}
+
+		size := float64(10)
+		if maxRank > 0 {
+			size += math.Abs(rank) / maxRank * (maxSymbolSize - 10)
+		}
+
+		nodes = append(nodes, opts.GraphNode{
+			Name:       id,
+			Value:      float32(rank),
+			Symbol:     symbol,
+			SymbolSize: size,
+			Category:   category,
+			ItemStyle: &opts.ItemStyle{
+				Color: color,
+			},
+		})
+	}
+
+	var links []opts.GraphLink
+	for _, source := range sortedSources(graph.Edges) {
+		for _, targetKey := range sortedTargets(graph.Edges[source]) {
+			target := targetKey
+			value := float32(graph.Edges[source][targetKey])
+			if negNode, ok := graph.NegNodes[targetKey]; ok {
+				target = negNode.ID
+				value = -value
+			}
+			links = append(links, opts.GraphLink{
+				Source: source,
+				Target: target,
+				Value:  value,
+			})
+		}
+	}
+
+	return Data{
+		Nodes: nodes,
+		Links: links,
+	}
+}
+
+// Render writes a page with all the charts to w
+func Render(w io.Writer, charts ...components.Charter) error {
+	page := components.NewPage()
+	page.AddCharts(charts...)
+	return page.Render(w)
+}
+
+// nodeIDs returns the sorted ids of all the nodes in the graph and the results
+func nodeIDs(graph *rep.Graph, results map[string]rep.Ranks) []string {
+	set := map[string]bool{}
+	for key := range graph.Nodes {
+		if negNode, ok := graph.NegNodes[key]; ok {
+			set[negNode.ID] = true
+			continue
+		}
+		set[key] = true
+	}
+	for _, node := range graph.NegNodes {
+		set[node.ID] = true
+	}
+	for id := range results {
+		set[id] = true
+	}
+
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedSources(edges map[string](map[string]float64)) []string {
+	keys := make([]string, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedTargets(targets map[string]float64) []string {
+	keys := make([]string, 0, len(targets))
+	for key := range targets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+
+	return false
+}
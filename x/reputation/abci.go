@@ -0,0 +1,48 @@
+package reputation
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/x/reputation/keeper"
+	"github.com/relevant-community/reputation/x/reputation/types"
+)
+
+// EndBlocker recomputes the ranks every RankInterval blocks
+// ranks are computed on a cached context and only written if ranking succeeds
+// so a failure doesn't halt the chain, the previous ranks are kept instead
+func EndBlocker(ctx sdk.Context, k keeper.Keeper) {
+	params := k.GetParams(ctx)
+	if params.RankInterval == 0 || ctx.BlockHeight()%int64(params.RankInterval) != 0 {
+		return
+	}
+	defer telemetry.ModuleMeasureSince(types.ModuleName, time.Now(), telemetry.MetricKeyEndBlocker)
+
+	cacheCtx, write := ctx.CacheContext()
+	n, err := computeRanks(cacheCtx, k)
+	if err != nil {
+		k.Logger(ctx).Error("failed to compute ranks", "height", ctx.BlockHeight(), "err", err)
+		return
+	}
+	write()
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeRank,
+		sdk.NewAttribute(types.AttributeKeyNodes, strconv.Itoa(n)),
+		sdk.NewAttribute(types.AttributeKeyCommitment, hex.EncodeToString(k.GetCommitment(ctx))),
+	))
+}
+
+// computeRanks turns panics from the ranking engine into errors
+func computeRanks(ctx sdk.Context, k keeper.Keeper) (n int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("ranking panicked: %v", r)
+		}
+	}()
+	return k.ComputeRanks(ctx)
+}
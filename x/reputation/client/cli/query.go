@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/relevant-community/reputation/x/reputation/types"
+	"github.com/spf13/cobra"
+)
+
+// GetQueryCmd returns the query commands of the module
+func GetQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Querying commands for the reputation module",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+	cmd.AddCommand(NewRankCmd(), NewTopCmd())
+	return cmd
+}
+
+// NewRankCmd returns a command to query the ranks of a node
+func NewRankCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rank [id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Query the positive and negative ranks of a node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			res, err := types.NewQueryClient(clientCtx).Rank(context.Background(), &types.QueryRankRequest{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// NewTopCmd returns a command to query the nodes with the highest net rank
+func NewTopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top [limit]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Query the nodes with the highest net rank",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &types.QueryTopRequest{}
+			if len(args) > 0 {
+				limit, err := strconv.ParseUint(args[0], 10, 32)
+				if err != nil {
+					return err
+				}
+				req.Limit = uint32(limit)
+			}
+
+			res, err := types.NewQueryClient(clientCtx).Top(context.Background(), req)
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
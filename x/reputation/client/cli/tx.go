@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/version"
+	govcli "github.com/cosmos/cosmos-sdk/x/gov/client/cli"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/relevant-community/reputation/x/reputation/types"
+	"github.com/spf13/cobra"
+)
+
+// flags of the personalization proposal
+const (
+	FlagAdd    = "add"
+	FlagRemove = "remove"
+)
+
+// GetTxCmd returns the transaction commands of the module
+func GetTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Reputation transaction subcommands",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+	cmd.AddCommand(NewVoteCmd())
+	return cmd
+}
+
+// NewVoteCmd returns a command to vote for a node
+func NewVoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vote [target] [up|down|none]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Vote for a node, none removes a previous vote",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			vote, err := types.ParseVote(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgVote(clientCtx.GetFromAddress(), args[0], vote)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// NewSubmitPersonalizationProposalCmd returns a command to propose personalization changes
+func NewSubmitPersonalizationProposalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reputation-personalization [flags]",
+		Args:  cobra.ExactArgs(0),
+		Short: "Submit a proposal to add or remove personalization nodes",
+		Example: fmt.Sprintf(
+			"%s tx gov submit-proposal reputation-personalization --add=a,b --remove=c --title=... --description=... --deposit=...",
+			version.AppName,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			title, err := cmd.Flags().GetString(govcli.FlagTitle)
+			if err != nil {
+				return err
+			}
+			description, err := cmd.Flags().GetString(govcli.FlagDescription)
+			if err != nil {
+				return err
+			}
+			add, err := cmd.Flags().GetStringSlice(FlagAdd)
+			if err != nil {
+				return err
+			}
+			remove, err := cmd.Flags().GetStringSlice(FlagRemove)
+			if err != nil {
+				return err
+			}
+			depositStr, err := cmd.Flags().GetString(govcli.FlagDeposit)
+			if err != nil {
+				return err
+			}
+			deposit, err := sdk.ParseCoinsNormalized(depositStr)
+			if err != nil {
+				return err
+			}
+
+			content := types.NewPersonalizationProposal(title, description, add, remove)
+			msg, err := govtypes.NewMsgSubmitProposal(content, deposit, clientCtx.GetFromAddress())
+			if err != nil {
+				return err
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+	cmd.Flags().StringSlice(FlagAdd, nil, "comma separated ids to add to the personalization vector")
+	cmd.Flags().StringSlice(FlagRemove, nil, "comma separated ids to remove from the personalization vector")
+	cmd.MarkFlagRequired(govcli.FlagTitle)
+	cmd.MarkFlagRequired(govcli.FlagDescription)
+	return cmd
+}
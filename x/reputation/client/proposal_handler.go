@@ -0,0 +1,10 @@
+package client
+
+import (
+	govclient "github.com/cosmos/cosmos-sdk/x/gov/client"
+	"github.com/relevant-community/reputation/x/reputation/client/cli"
+	"github.com/relevant-community/reputation/x/reputation/client/rest"
+)
+
+// ProposalHandler is the gov client handler of personalization proposals
+var ProposalHandler = govclient.NewProposalHandler(cli.NewSubmitPersonalizationProposalCmd, rest.ProposalRESTHandler)
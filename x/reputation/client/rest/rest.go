@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	govrest "github.com/cosmos/cosmos-sdk/x/gov/client/rest"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/gorilla/mux"
+	"github.com/relevant-community/reputation/x/reputation/types"
+)
+
+// VoteRequest is the body of a vote request
+type VoteRequest struct {
+	BaseReq rest.BaseReq `json:"base_req" yaml:"base_req"`
+	Target  string       `json:"target" yaml:"target"`
+	Vote    string       `json:"vote" yaml:"vote"` // up, down or none
+}
+
+// PersonalizationProposalRequest is the body of a personalization proposal request
+type PersonalizationProposalRequest struct {
+	BaseReq     rest.BaseReq `json:"base_req" yaml:"base_req"`
+	Title       string       `json:"title" yaml:"title"`
+	Description string       `json:"description" yaml:"description"`
+	Deposit     sdk.Coins    `json:"deposit" yaml:"deposit"`
+	Add         []string     `json:"add" yaml:"add"`
+	Remove      []string     `json:"remove" yaml:"remove"`
+}
+
+// RegisterRoutes registers the legacy REST routes of the module
+func RegisterRoutes(clientCtx client.Context, r *mux.Router) {
+	r.HandleFunc("/reputation/rank/{id}", queryHandler(clientCtx, types.QueryRank, "id")).Methods("GET")
+	r.HandleFunc("/reputation/top", queryHandler(clientCtx, types.QueryTop, "")).Methods("GET")
+	r.HandleFunc("/reputation/top/{limit}", queryHandler(clientCtx, types.QueryTop, "limit")).Methods("GET")
+	r.HandleFunc("/reputation/vote", newVoteHandler(clientCtx)).Methods("POST")
+}
+
+// ProposalRESTHandler returns the REST handler of personalization proposals
+func ProposalRESTHandler(clientCtx client.Context) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "reputation_personalization",
+		Handler:  newProposalHandler(clientCtx),
+	}
+}
+
+// queryHandler forwards the request to the legacy querier, arg is an optional path variable
+func queryHandler(clientCtx client.Context, path string, arg string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		clientCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, clientCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, path)
+		if arg != "" {
+			route += "/" + mux.Vars(r)[arg]
+		}
+
+		res, height, err := clientCtx.Query(route)
+		if rest.CheckInternalServerError(w, err) {
+			return
+		}
+
+		clientCtx = clientCtx.WithHeight(height)
+		rest.PostProcessResponse(w, clientCtx, res)
+	}
+}
+
+func newVoteHandler(clientCtx client.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req VoteRequest
+		if !rest.ReadRESTReq(w, r, clientCtx.LegacyAmino, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		voter, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if rest.CheckBadRequestError(w, err) {
+			return
+		}
+		vote, err := types.ParseVote(req.Vote)
+		if rest.CheckBadRequestError(w, err) {
+			return
+		}
+
+		msg := types.NewMsgVote(voter, req.Target, vote)
+		if rest.CheckBadRequestError(w, msg.ValidateBasic()) {
+			return
+		}
+		tx.WriteGeneratedTxResponse(clientCtx, w, req.BaseReq, msg)
+	}
+}
+
+func newProposalHandler(clientCtx client.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req PersonalizationProposalRequest
+		if !rest.ReadRESTReq(w, r, clientCtx.LegacyAmino, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		from, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if rest.CheckBadRequestError(w, err) {
+			return
+		}
+
+		content := types.NewPersonalizationProposal(req.Title, req.Description, req.Add, req.Remove)
+		msg, err := govtypes.NewMsgSubmitProposal(content, req.Deposit, from)
+		if rest.CheckBadRequestError(w, err) {
+			return
+		}
+		if rest.CheckBadRequestError(w, msg.ValidateBasic()) {
+			return
+		}
+		tx.WriteGeneratedTxResponse(clientCtx, w, req.BaseReq, msg)
+	}
+}
@@ -0,0 +1,47 @@
+package reputation
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/x/reputation/keeper"
+	"github.com/relevant-community/reputation/x/reputation/types"
+)
+
+// InitGenesis initializes the module state from genesis
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+	k.UpdatePersonalization(ctx, gs.Personalization, nil)
+
+	for _, vote := range gs.Votes {
+		voter, err := sdk.AccAddressFromBech32(vote.Voter)
+		if err != nil {
+			panic(err)
+		}
+		k.Vote(ctx, voter, vote.Target, vote.Vote)
+	}
+
+	// ranks are set last so they overwrite the empty nodes created by votes
+	for _, rank := range gs.Ranks {
+		result, err := rank.Result()
+		if err != nil {
+			panic(err)
+		}
+		k.SetRank(ctx, result)
+	}
+}
+
+// ExportGenesis exports the module state
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	gs := types.DefaultGenesis()
+	gs.Params = k.GetParams(ctx)
+	gs.Personalization = k.GetPersonalization(ctx)
+
+	k.IterateVotes(ctx, func(vote types.VoteRecord) bool {
+		gs.Votes = append(gs.Votes, vote)
+		return false
+	})
+	k.IterateRanks(ctx, func(rank types.NodeRank) bool {
+		gs.Ranks = append(gs.Ranks, rank)
+		return false
+	})
+	return gs
+}
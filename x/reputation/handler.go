@@ -0,0 +1,68 @@
+package reputation
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/relevant-community/reputation/x/reputation/keeper"
+	"github.com/relevant-community/reputation/x/reputation/types"
+)
+
+// NewHandler returns a handler for the module messages
+func NewHandler(k keeper.Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) (*sdk.Result, error) {
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+		switch msg := msg.(type) {
+		case *types.MsgVote:
+			return handleMsgVote(ctx, k, msg)
+		default:
+			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s message type: %T", types.ModuleName, msg)
+		}
+	}
+}
+
+func handleMsgVote(ctx sdk.Context, k keeper.Keeper, msg *types.MsgVote) (*sdk.Result, error) {
+	voter, err := sdk.AccAddressFromBech32(msg.Voter)
+	if err != nil {
+		return nil, err
+	}
+
+	k.Vote(ctx, voter, msg.Target, msg.Vote)
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeVote,
+			sdk.NewAttribute(types.AttributeKeyVoter, msg.Voter),
+			sdk.NewAttribute(types.AttributeKeyTarget, msg.Target),
+			sdk.NewAttribute(types.AttributeKeyVote, msg.Vote.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Voter),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().ABCIEvents()}, nil
+}
+
+// NewProposalHandler returns a governance handler for personalization proposals
+func NewProposalHandler(k keeper.Keeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) error {
+		switch c := content.(type) {
+		case *types.PersonalizationProposal:
+			k.UpdatePersonalization(ctx, c.Add, c.Remove)
+			ctx.EventManager().EmitEvent(sdk.NewEvent(
+				types.EventTypePersonalization,
+				sdk.NewAttribute(types.AttributeKeyAdded, strings.Join(c.Add, ",")),
+				sdk.NewAttribute(types.AttributeKeyRemoved, strings.Join(c.Remove, ",")),
+			))
+			return nil
+		default:
+			return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s proposal content type: %T", types.ModuleName, c)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/x/reputation/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// Rank implements types.QueryServer
+func (k Keeper) Rank(c context.Context, req *types.QueryRankRequest) (*types.QueryRankResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(c)
+
+	rank, ok := k.GetRank(ctx, req.Id)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "node %s not found", req.Id)
+	}
+	return &types.QueryRankResponse{Rank: &rank}, nil
+}
+
+// Top implements types.QueryServer
+func (k Keeper) Top(c context.Context, req *types.QueryTopRequest) (*types.QueryTopResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(c)
+
+	limit, err := topLimit(req.Limit)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ranks := k.TopRanks(ctx, limit)
+	res := &types.QueryTopResponse{Ranks: make([]*types.NodeRank, len(ranks))}
+	for i := range ranks {
+		res.Ranks[i] = &ranks[i]
+	}
+	return res, nil
+}
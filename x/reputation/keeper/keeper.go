@@ -0,0 +1,170 @@
+package keeper
+
+import (
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/detrep"
+	"github.com/relevant-community/reputation/detrep/kvstore"
+	"github.com/relevant-community/reputation/detrep/sdkadapter"
+	"github.com/relevant-community/reputation/x/reputation/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// Keeper stores votes and ranks
+// the graph lives under types.GraphPrefix in the layout of detrep/kvstore
+type Keeper struct {
+	storeKey sdk.StoreKey
+}
+
+// NewKeeper returns a new keeper
+func NewKeeper(storeKey sdk.StoreKey) Keeper {
+	return Keeper{storeKey: storeKey}
+}
+
+// Logger returns a module logger
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// graph returns the graph store
+func (k Keeper) graph(ctx sdk.Context) kvstore.Store {
+	return kvstore.NewStore(prefix.NewStore(ctx.KVStore(k.storeKey), types.GraphPrefix))
+}
+
+// GetParams returns the module params
+func (k Keeper) GetParams(ctx sdk.Context) types.Params {
+	var params types.Params
+	bz := ctx.KVStore(k.storeKey).Get(types.ParamsKey)
+	if bz == nil {
+		return params
+	}
+	types.ModuleCdc.LegacyAmino.MustUnmarshalBinaryBare(bz, &params)
+	return params
+}
+
+// SetParams stores the module params
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	ctx.KVStore(k.storeKey).Set(types.ParamsKey, types.ModuleCdc.LegacyAmino.MustMarshalBinaryBare(&params))
+	k.graph(ctx).SetParams(params.GraphParams())
+}
+
+// Vote sets the link from voter to target, VoteNone removes it
+func (k Keeper) Vote(ctx sdk.Context, voter sdk.AccAddress, target string, vote types.Vote) {
+	k.graph(ctx).SetEdge(voter.String(), target, sdkadapter.FromInt(vote.Weight()))
+}
+
+// GetVote returns the vote of voter for target
+func (k Keeper) GetVote(ctx sdk.Context, voter sdk.AccAddress, target string) types.Vote {
+	weight, ok := k.graph(ctx).GetEdge(voter.String(), target)
+	switch {
+	case !ok:
+		return types.VoteNone
+	case weight.IsNegative():
+		return types.VoteDown
+	}
+	return types.VoteUp
+}
+
+// IterateVotes calls cb for every vote ordered by voter and target until it returns true
+func (k Keeper) IterateVotes(ctx sdk.Context, cb func(vote types.VoteRecord) (stop bool)) {
+	k.graph(ctx).IterateEdges(func(source, target string, weight detrep.Int) bool {
+		vote := types.VoteUp
+		if weight.IsNegative() {
+			vote = types.VoteDown
+		}
+		return cb(types.VoteRecord{Voter: source, Target: target, Vote: vote})
+	})
+}
+
+// GetPersonalization returns the personalization nodes sorted by id
+func (k Keeper) GetPersonalization(ctx sdk.Context) []string {
+	return k.graph(ctx).Personalization()
+}
+
+// UpdatePersonalization adds and removes personalization nodes
+func (k Keeper) UpdatePersonalization(ctx sdk.Context, add []string, remove []string) {
+	graph := k.graph(ctx)
+	for _, id := range add {
+		graph.AddPersonalizationNode(id)
+	}
+	for _, id := range remove {
+		graph.RemovePersonalizationNode(id)
+	}
+}
+
+// GetRank returns the cached ranks of a node
+func (k Keeper) GetRank(ctx sdk.Context, id string) (types.NodeRank, bool) {
+	node, ok := k.graph(ctx).GetNode(id)
+	if !ok {
+		return types.NodeRank{}, false
+	}
+	return types.NewNodeRank(detrep.Ranks{ID: node.ID, PRank: node.PRank, NRank: node.NRank}), true
+}
+
+// SetRank sets the cached ranks of a node
+func (k Keeper) SetRank(ctx sdk.Context, result detrep.Ranks) {
+	k.graph(ctx).SetNode(result.ID, result.PRank, result.NRank)
+}
+
+// IterateRanks calls cb for every node ordered by id until it returns true
+// this includes the negConsumer
+func (k Keeper) IterateRanks(ctx sdk.Context, cb func(rank types.NodeRank) (stop bool)) {
+	k.graph(ctx).IterateNodes(func(node detrep.Node) bool {
+		return cb(types.NewNodeRank(detrep.Ranks{ID: node.ID, PRank: node.PRank, NRank: node.NRank}))
+	})
+}
+
+// TopRanks returns up to limit nodes with the highest net rank (pRank - nRank)
+// ties are ordered by id, the negConsumer is not included
+func (k Keeper) TopRanks(ctx sdk.Context, limit int) []types.NodeRank {
+	type netRank struct {
+		result detrep.Ranks
+		net    sdk.Int
+	}
+	var nodes []netRank
+	k.graph(ctx).IterateNodes(func(node detrep.Node) bool {
+		if node.ID == detrep.NegConsumerID {
+			return false
+		}
+		net := sdk.NewIntFromBigInt(node.PRank.BigInt()).Sub(sdk.NewIntFromBigInt(node.NRank.BigInt()))
+		nodes = append(nodes, netRank{detrep.Ranks{ID: node.ID, PRank: node.PRank, NRank: node.NRank}, net})
+		return false
+	})
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].net.GT(nodes[j].net)
+	})
+	if len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+
+	ranks := make([]types.NodeRank, len(nodes))
+	for i, node := range nodes {
+		ranks[i] = types.NewNodeRank(node.result)
+	}
+	return ranks
+}
+
+// ComputeRanks ranks the graph and stores the results as cached ranks
+// it returns the number of ranked nodes, or an error and stores nothing
+// if the ranks don't converge within MaxRankIterations
+func (k Keeper) ComputeRanks(ctx sdk.Context) (int, error) {
+	store := k.graph(ctx)
+	graph, err := store.Load()
+	if err != nil {
+		return 0, err
+	}
+	graph.MaxIterations = types.MaxRankIterations
+	results, err := store.RankGraph(graph)
+	if err != nil {
+		return 0, err
+	}
+	return len(results), nil
+}
+
+// GetCommitment returns the commitment of the last computed ranks, nil if ranks were never computed
+func (k Keeper) GetCommitment(ctx sdk.Context) []byte {
+	return k.graph(ctx).Commitment()
+}
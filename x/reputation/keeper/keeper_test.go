@@ -0,0 +1,200 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/x/reputation/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+var (
+	seed  = sdk.AccAddress([]byte("seed________________"))
+	alice = sdk.AccAddress([]byte("alice_______________"))
+	bob   = sdk.AccAddress([]byte("bob_________________"))
+)
+
+func setup(t *testing.T) (sdk.Context, Keeper) {
+	key := sdk.NewKVStoreKey(types.StoreKey)
+	db := dbm.NewMemDB()
+	cms := store.NewCommitMultiStore(db)
+	cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	if err := cms.LoadLatestVersion(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := sdk.NewContext(cms, tmproto.Header{Height: 1}, false, log.NewNopLogger())
+
+	k := NewKeeper(key)
+	k.SetParams(ctx, types.DefaultParams())
+	return ctx, k
+}
+
+// rankedGraph ranks a small graph: seed trusts alice, alice upvotes bob, bob is downvoted by seed
+func rankedGraph(t *testing.T) (sdk.Context, Keeper) {
+	ctx, k := setup(t)
+	k.UpdatePersonalization(ctx, []string{seed.String()}, nil)
+	k.Vote(ctx, seed, alice.String(), types.VoteUp)
+	k.Vote(ctx, seed, bob.String(), types.VoteDown)
+	k.Vote(ctx, alice, bob.String(), types.VoteUp)
+	k.Vote(ctx, alice, "post1", types.VoteUp)
+
+	for pass := 0; pass < 2; pass++ {
+		if _, err := k.ComputeRanks(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ctx, k
+}
+
+func TestVotes(t *testing.T) {
+	ctx, k := setup(t)
+
+	k.Vote(ctx, alice, "post1", types.VoteUp)
+	k.Vote(ctx, alice, "post2", types.VoteDown)
+	if k.GetVote(ctx, alice, "post1") != types.VoteUp || k.GetVote(ctx, alice, "post2") != types.VoteDown {
+		t.Errorf("votes were not stored")
+	}
+
+	k.Vote(ctx, alice, "post1", types.VoteNone)
+	if k.GetVote(ctx, alice, "post1") != types.VoteNone {
+		t.Errorf("VoteNone should remove the vote")
+	}
+
+	var votes []types.VoteRecord
+	k.IterateVotes(ctx, func(vote types.VoteRecord) bool {
+		votes = append(votes, vote)
+		return false
+	})
+	if len(votes) != 1 || votes[0] != (types.VoteRecord{Voter: alice.String(), Target: "post2", Vote: types.VoteDown}) {
+		t.Errorf("unexpected votes %v", votes)
+	}
+}
+
+func TestParams(t *testing.T) {
+	ctx, k := setup(t)
+	params := types.DefaultParams()
+	params.RankInterval = 7
+	k.SetParams(ctx, params)
+
+	stored := k.GetParams(ctx)
+	if stored.RankInterval != 7 || !stored.Alpha.Equal(params.Alpha) || !stored.Epsilon.Equal(params.Epsilon) {
+		t.Errorf("expected %v got %v", params, stored)
+	}
+}
+
+func TestComputeRanksNotConverged(t *testing.T) {
+	// without teleports the rank of alice and bob swaps back and forth forever
+	ctx, k := setup(t)
+	params := types.DefaultParams()
+	params.Alpha = sdk.OneDec()
+	k.SetParams(ctx, params)
+	k.Vote(ctx, alice, bob.String(), types.VoteUp)
+	k.Vote(ctx, bob, alice.String(), types.VoteUp)
+	k.Vote(ctx, seed, alice.String(), types.VoteUp)
+
+	if _, err := k.ComputeRanks(ctx); err == nil {
+		t.Fatal("expected an error")
+	}
+	if k.GetCommitment(ctx) != nil {
+		t.Error("nothing should be stored")
+	}
+}
+
+func TestGRPCQueries(t *testing.T) {
+	ctx, k := rankedGraph(t)
+
+	registry := codectypes.NewInterfaceRegistry()
+	helper := baseapp.NewQueryServerTestHelper(ctx, registry)
+	types.RegisterQueryServer(helper, k)
+	client := types.NewQueryClient(helper)
+
+	res, err := client.Rank(context.Background(), &types.QueryRankRequest{Id: bob.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := k.GetRank(ctx, bob.String())
+	if *res.Rank != expected {
+		t.Errorf("expected %v got %v", expected, res.Rank)
+	}
+	if res.Rank.NRank == "0.000000000000000000" {
+		t.Errorf("bob should have a negative rank")
+	}
+
+	if _, err := client.Rank(context.Background(), &types.QueryRankRequest{Id: "missing"}); err == nil {
+		t.Errorf("expected an error for a missing node")
+	}
+
+	top, err := client.Top(context.Background(), &types.QueryTopRequest{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top.Ranks) != 2 || top.Ranks[0].Id != seed.String() {
+		t.Errorf("expected the seed to have the highest rank %v", top.Ranks)
+	}
+
+	if _, err := client.Top(context.Background(), &types.QueryTopRequest{Limit: types.MaxTopLimit + 1}); err == nil {
+		t.Errorf("expected an error above the max limit")
+	}
+}
+
+func TestTopRanks(t *testing.T) {
+	ctx, k := rankedGraph(t)
+
+	ranks := k.TopRanks(ctx, 100)
+	net := func(rank types.NodeRank) sdk.Dec {
+		return sdk.MustNewDecFromStr(rank.PRank).Sub(sdk.MustNewDecFromStr(rank.NRank))
+	}
+	for i, rank := range ranks {
+		if rank.Id == "negConsumer" {
+			t.Errorf("the negConsumer should not be listed")
+		}
+		if i > 0 && net(rank).GT(net(ranks[i-1])) {
+			t.Errorf("ranks should be sorted by net rank %v", ranks)
+		}
+	}
+	if len(ranks) != 4 {
+		t.Errorf("expected 4 nodes got %d", len(ranks))
+	}
+}
+
+func TestLegacyQuerier(t *testing.T) {
+	ctx, k := rankedGraph(t)
+	querier := NewQuerier(k, codec.NewLegacyAmino())
+
+	bz, err := querier(ctx, []string{types.QueryRank, "post1"}, abci.RequestQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rank types.NodeRank
+	if err := json.Unmarshal(bz, &rank); err != nil {
+		t.Fatal(err)
+	}
+	if expected, _ := k.GetRank(ctx, "post1"); rank != expected {
+		t.Errorf("expected %v got %v", expected, rank)
+	}
+
+	bz, err = querier(ctx, []string{types.QueryTop, "1"}, abci.RequestQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var top []types.NodeRank
+	if err := json.Unmarshal(bz, &top); err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 1 {
+		t.Errorf("expected 1 node got %v", top)
+	}
+
+	if _, err := querier(ctx, []string{"unknown"}, abci.RequestQuery{}); err == nil {
+		t.Errorf("expected an error for an unknown path")
+	}
+}
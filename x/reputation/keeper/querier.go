@@ -0,0 +1,65 @@
+package keeper
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/relevant-community/reputation/x/reputation/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// NewQuerier returns the legacy querier
+// rank/<id> returns the ranks of a node, top/<limit> the nodes with the highest net rank
+func NewQuerier(k Keeper, legacyQuerierCdc *codec.LegacyAmino) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, error) {
+		var res interface{}
+		switch path[0] {
+		case types.QueryRank:
+			if len(path) < 2 {
+				return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "missing node id")
+			}
+			rank, ok := k.GetRank(ctx, path[1])
+			if !ok {
+				return nil, sdkerrors.Wrap(types.ErrNodeNotFound, path[1])
+			}
+			res = rank
+
+		case types.QueryTop:
+			var limit uint64
+			if len(path) > 1 {
+				var err error
+				if limit, err = strconv.ParseUint(path[1], 10, 32); err != nil {
+					return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid limit %s", path[1])
+				}
+			}
+			n, err := topLimit(uint32(limit))
+			if err != nil {
+				return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+			}
+			res = k.TopRanks(ctx, n)
+
+		default:
+			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unknown %s query endpoint: %s", types.ModuleName, path[0])
+		}
+
+		bz, err := codec.MarshalJSONIndent(legacyQuerierCdc, res)
+		if err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+		}
+		return bz, nil
+	}
+}
+
+// topLimit applies the default and max limits
+func topLimit(limit uint32) (int, error) {
+	switch {
+	case limit == 0:
+		return types.DefaultTopLimit, nil
+	case limit > types.MaxTopLimit:
+		return 0, fmt.Errorf("limit %d is above the max of %d", limit, types.MaxTopLimit)
+	}
+	return int(limit), nil
+}
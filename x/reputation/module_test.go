@@ -0,0 +1,132 @@
+package reputation
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/relevant-community/reputation/x/reputation/keeper"
+	"github.com/relevant-community/reputation/x/reputation/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+var (
+	seed  = sdk.AccAddress([]byte("seed________________"))
+	alice = sdk.AccAddress([]byte("alice_______________"))
+)
+
+func setup(t *testing.T, gs *types.GenesisState) (sdk.Context, keeper.Keeper) {
+	key := sdk.NewKVStoreKey(types.StoreKey)
+	db := dbm.NewMemDB()
+	cms := store.NewCommitMultiStore(db)
+	cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	if err := cms.LoadLatestVersion(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := sdk.NewContext(cms, tmproto.Header{Height: 1}, false, log.NewNopLogger())
+
+	k := keeper.NewKeeper(key)
+	InitGenesis(ctx, k, *gs)
+	return ctx, k
+}
+
+func testGenesis() *types.GenesisState {
+	gs := types.DefaultGenesis()
+	gs.Params.RankInterval = 5
+	gs.Personalization = []string{seed.String()}
+	gs.Votes = []types.VoteRecord{
+		{Voter: seed.String(), Target: alice.String(), Vote: types.VoteUp},
+		{Voter: alice.String(), Target: "post1", Vote: types.VoteUp},
+	}
+	return gs
+}
+
+func TestHandler(t *testing.T) {
+	ctx, k := setup(t, testGenesis())
+	handler := NewHandler(k)
+
+	res, err := handler(ctx, types.NewMsgVote(alice, "post2", types.VoteDown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Events) == 0 || res.Events[0].Type != types.EventTypeVote {
+		t.Errorf("expected a vote event %v", res.Events)
+	}
+	if k.GetVote(ctx, alice, "post2") != types.VoteDown {
+		t.Errorf("vote was not stored")
+	}
+
+	if _, err := handler(ctx, &govtypes.MsgDeposit{}); err == nil {
+		t.Errorf("expected an error for an unknown message")
+	}
+}
+
+func TestProposalHandler(t *testing.T) {
+	ctx, k := setup(t, testGenesis())
+	handler := NewProposalHandler(k)
+
+	proposal := types.NewPersonalizationProposal("seeds", "update seeds", []string{alice.String()}, []string{seed.String()})
+	if err := proposal.ValidateBasic(); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler(ctx, proposal); err != nil {
+		t.Fatal(err)
+	}
+
+	personalization := k.GetPersonalization(ctx)
+	if len(personalization) != 1 || personalization[0] != alice.String() {
+		t.Errorf("expected alice to be the only seed %v", personalization)
+	}
+}
+
+func TestEndBlocker(t *testing.T) {
+	ctx, k := setup(t, testGenesis())
+
+	// ranks are only computed every RankInterval blocks
+	EndBlocker(ctx.WithBlockHeight(4), k)
+	if rank, _ := k.GetRank(ctx, "post1"); rank.PRank != "0.000000000000000000" {
+		t.Errorf("ranks should not be computed before the interval %v", rank)
+	}
+	if k.GetCommitment(ctx) != nil {
+		t.Error("there should be no commitment before ranks are computed")
+	}
+
+	ctx = ctx.WithBlockHeight(5).WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, k)
+	if rank, _ := k.GetRank(ctx, "post1"); rank.PRank == "0.000000000000000000" {
+		t.Errorf("post1 should have a rank after the interval %v", rank)
+	}
+	events := ctx.EventManager().Events()
+	if len(events) != 1 || events[0].Type != types.EventTypeRank {
+		t.Errorf("expected a rank event %v", events)
+	}
+	if len(k.GetCommitment(ctx)) != sha256.Size {
+		t.Errorf("expected a commitment of the ranks, got %X", k.GetCommitment(ctx))
+	}
+}
+
+func TestGenesisRoundTrip(t *testing.T) {
+	ctx, k := setup(t, testGenesis())
+	EndBlocker(ctx.WithBlockHeight(5), k)
+
+	exported := ExportGenesis(ctx, k)
+	if err := exported.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx2, k2 := setup(t, exported)
+	again := ExportGenesis(ctx2, k2)
+
+	a := types.ModuleCdc.LegacyAmino.MustMarshalJSON(exported)
+	b := types.ModuleCdc.LegacyAmino.MustMarshalJSON(again)
+	if string(a) != string(b) {
+		t.Errorf("genesis should survive an export and import\n%s\n%s", a, b)
+	}
+	if len(exported.Votes) != 2 || len(exported.Ranks) == 0 {
+		t.Errorf("unexpected exported state %s", a)
+	}
+}
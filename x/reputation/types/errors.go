@@ -0,0 +1,15 @@
+package types
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// module errors
+var (
+	ErrInvalidVote     = sdkerrors.Register(ModuleName, 2, "invalid vote")
+	ErrInvalidTarget   = sdkerrors.Register(ModuleName, 3, "invalid target")
+	ErrSelfVote        = sdkerrors.Register(ModuleName, 4, "cannot vote for yourself")
+	ErrInvalidProposal = sdkerrors.Register(ModuleName, 5, "invalid personalization proposal")
+	ErrInvalidParams   = sdkerrors.Register(ModuleName, 6, "invalid params")
+	ErrNodeNotFound    = sdkerrors.Register(ModuleName, 7, "node not found")
+)
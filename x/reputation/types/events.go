@@ -0,0 +1,18 @@
+package types
+
+// events emitted by the module
+const (
+	EventTypeVote            = "vote"
+	EventTypeRank            = "rank"
+	EventTypePersonalization = "personalization"
+
+	AttributeKeyVoter      = "voter"
+	AttributeKeyTarget     = "target"
+	AttributeKeyVote       = "vote"
+	AttributeKeyNodes      = "nodes"
+	AttributeKeyCommitment = "commitment"
+	AttributeKeyAdded      = "added"
+	AttributeKeyRemoved    = "removed"
+
+	AttributeValueCategory = ModuleName
+)
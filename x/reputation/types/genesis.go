@@ -0,0 +1,54 @@
+package types
+
+// VoteRecord is a vote stored in genesis
+type VoteRecord struct {
+	Voter  string `json:"voter" yaml:"voter"`
+	Target string `json:"target" yaml:"target"`
+	Vote   Vote   `json:"vote" yaml:"vote"`
+}
+
+// GenesisState is the state of the module
+// ranks are the cached ranks the next computation starts from
+type GenesisState struct {
+	Params          Params       `json:"params" yaml:"params"`
+	Personalization []string     `json:"personalization" yaml:"personalization"`
+	Votes           []VoteRecord `json:"votes" yaml:"votes"`
+	Ranks           []NodeRank   `json:"ranks" yaml:"ranks"`
+}
+
+// DefaultGenesis returns the default genesis state
+func DefaultGenesis() *GenesisState {
+	return &GenesisState{
+		Params:          DefaultParams(),
+		Personalization: []string{},
+		Votes:           []VoteRecord{},
+		Ranks:           []NodeRank{},
+	}
+}
+
+// Validate checks the genesis state
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+	for _, id := range gs.Personalization {
+		if err := ValidateID(id); err != nil {
+			return err
+		}
+	}
+	for _, vote := range gs.Votes {
+		msg := MsgVote{Voter: vote.Voter, Target: vote.Target, Vote: vote.Vote}
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	for _, rank := range gs.Ranks {
+		if err := ValidateID(rank.Id); err != nil {
+			return err
+		}
+		if _, err := rank.Result(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package types
+
+const (
+	// ModuleName is the name of the module
+	ModuleName = "reputation"
+
+	// StoreKey is the default store key of the module
+	StoreKey = ModuleName
+
+	// RouterKey is the message route of the module
+	RouterKey = ModuleName
+
+	// QuerierRoute is the legacy querier route of the module
+	QuerierRoute = ModuleName
+)
+
+// store prefixes
+var (
+	GraphPrefix = []byte{0x01} // graph data, see detrep/kvstore
+	ParamsKey   = []byte{0x02} // module params
+)
@@ -0,0 +1,67 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// TypeMsgVote is the type of MsgVote
+const TypeMsgVote = "vote"
+
+// MaxIDLength is the max length of a vote target, ids are length prefixed in the store
+const MaxIDLength = 255
+
+var _ sdk.Msg = &MsgVote{}
+
+// NewMsgVote returns a new vote message
+func NewMsgVote(voter sdk.AccAddress, target string, vote Vote) *MsgVote {
+	return &MsgVote{Voter: voter.String(), Target: target, Vote: vote}
+}
+
+// Route implements sdk.Msg
+func (m MsgVote) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (m MsgVote) Type() string { return TypeMsgVote }
+
+// ValidateBasic implements sdk.Msg
+func (m MsgVote) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(m.Voter); err != nil {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid voter address (%s)", err)
+	}
+	if err := ValidateID(m.Target); err != nil {
+		return err
+	}
+	if m.Target == m.Voter {
+		return ErrSelfVote
+	}
+	if !m.Vote.Valid() {
+		return sdkerrors.Wrapf(ErrInvalidVote, "unknown vote %d", m.Vote)
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m MsgVote) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&m))
+}
+
+// GetSigners implements sdk.Msg
+func (m MsgVote) GetSigners() []sdk.AccAddress {
+	voter, err := sdk.AccAddressFromBech32(m.Voter)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{voter}
+}
+
+// ValidateID checks a node id can be stored
+func ValidateID(id string) error {
+	if id == "" {
+		return sdkerrors.Wrap(ErrInvalidTarget, "id cannot be empty")
+	}
+	if len(id) > MaxIDLength {
+		return sdkerrors.Wrapf(ErrInvalidTarget, "id is longer than %d bytes", MaxIDLength)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/relevant-community/reputation/detrep"
+	"github.com/relevant-community/reputation/detrep/kvstore"
+)
+
+// default params
+var (
+	DefaultAlpha        = sdk.NewDecWithPrec(85, 2)
+	DefaultEpsilon      = sdk.NewDecWithPrec(1, 6)
+	DefaultRankInterval = uint64(100)
+)
+
+// MinEpsilon is the smallest epsilon, 10^9 detrep units
+// a smaller one can be within the rounding error of an iteration and keep ranking from converging
+var MinEpsilon = sdk.NewDecWithPrec(1, 9)
+
+// MaxRankIterations bounds the iterations of a rank computation, the epoch fails if ranks don't converge
+const MaxRankIterations = 1000
+
+// Params are the module parameters
+type Params struct {
+	Alpha        sdk.Dec `json:"alpha" yaml:"alpha"`                 // damping factor
+	Epsilon      sdk.Dec `json:"epsilon" yaml:"epsilon"`             // convergence threshold
+	RankInterval uint64  `json:"rank_interval" yaml:"rank_interval"` // ranks are computed every RankInterval blocks
+}
+
+// DefaultParams returns the default params
+func DefaultParams() Params {
+	return Params{Alpha: DefaultAlpha, Epsilon: DefaultEpsilon, RankInterval: DefaultRankInterval}
+}
+
+// Validate checks the params are usable
+func (p Params) Validate() error {
+	if p.Alpha.IsNil() || !p.Alpha.IsPositive() || p.Alpha.GT(sdk.OneDec()) {
+		return sdkerrors.Wrapf(ErrInvalidParams, "alpha must be in (0, 1], got %s", p.Alpha)
+	}
+	if p.Epsilon.IsNil() || p.Epsilon.LT(MinEpsilon) {
+		return sdkerrors.Wrapf(ErrInvalidParams, "epsilon must be at least %s, got %s", MinEpsilon, p.Epsilon)
+	}
+	if p.RankInterval == 0 {
+		return sdkerrors.Wrap(ErrInvalidParams, "rank interval must be positive")
+	}
+	return nil
+}
+
+// GraphParams converts the params to detrep precision
+// sdk.Dec has the same 18 decimals as detrep
+func (p Params) GraphParams() kvstore.Params {
+	return kvstore.Params{
+		Alpha:   detrep.NewUintFromBigInt(p.Alpha.BigInt()),
+		Epsilon: detrep.NewUintFromBigInt(p.Epsilon.BigInt()),
+	}
+}
+
+func (p Params) String() string {
+	return fmt.Sprintf("Params:\n  Alpha:         %s\n  Epsilon:       %s\n  Rank Interval: %d\n", p.Alpha, p.Epsilon, p.RankInterval)
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+// ProposalTypePersonalization is the type of PersonalizationProposal
+const ProposalTypePersonalization = "Personalization"
+
+var _ govtypes.Content = &PersonalizationProposal{}
+
+// NewPersonalizationProposal returns a new proposal
+func NewPersonalizationProposal(title, description string, add, remove []string) *PersonalizationProposal {
+	return &PersonalizationProposal{Title: title, Description: description, Add: add, Remove: remove}
+}
+
+// GetTitle implements govtypes.Content
+func (p *PersonalizationProposal) GetTitle() string { return p.Title }
+
+// GetDescription implements govtypes.Content
+func (p *PersonalizationProposal) GetDescription() string { return p.Description }
+
+// ProposalRoute implements govtypes.Content
+func (p *PersonalizationProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType implements govtypes.Content
+func (p *PersonalizationProposal) ProposalType() string { return ProposalTypePersonalization }
+
+// ValidateBasic implements govtypes.Content
+func (p *PersonalizationProposal) ValidateBasic() error {
+	if err := govtypes.ValidateAbstract(p); err != nil {
+		return err
+	}
+	if len(p.Add) == 0 && len(p.Remove) == 0 {
+		return sdkerrors.Wrap(ErrInvalidProposal, "proposal must add or remove nodes")
+	}
+
+	seen := map[string]bool{}
+	for _, id := range append(append([]string{}, p.Add...), p.Remove...) {
+		if err := ValidateID(id); err != nil {
+			return err
+		}
+		if seen[id] {
+			return sdkerrors.Wrapf(ErrInvalidProposal, "%s is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// String implements govtypes.Content
+func (p *PersonalizationProposal) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Personalization Proposal:\n  Title:       %s\n  Description: %s\n", p.Title, p.Description)
+	fmt.Fprintf(&b, "  Add:         %s\n  Remove:      %s\n", strings.Join(p.Add, ", "), strings.Join(p.Remove, ", "))
+	return b.String()
+}
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypePersonalization)
+	govtypes.RegisterProposalTypeCodec(&PersonalizationProposal{}, "reputation/PersonalizationProposal")
+}
@@ -0,0 +1,13 @@
+package types
+
+// legacy querier paths
+const (
+	QueryRank = "rank"
+	QueryTop  = "top"
+)
+
+// DefaultTopLimit is the number of nodes returned by Top when no limit is set
+const DefaultTopLimit = 10
+
+// MaxTopLimit is the max number of nodes returned by Top
+const MaxTopLimit = 1000
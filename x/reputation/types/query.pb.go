@@ -0,0 +1,966 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: reputation/v1/query.proto
+
+package types
+
+import (
+	context "context"
+	fmt "fmt"
+	grpc1 "github.com/gogo/protobuf/grpc"
+	proto "github.com/gogo/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type QueryRankRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *QueryRankRequest) Reset()         { *m = QueryRankRequest{} }
+func (m *QueryRankRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryRankRequest) ProtoMessage()    {}
+func (*QueryRankRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ab10d9aecdb4b4ec, []int{0}
+}
+func (m *QueryRankRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryRankRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRankRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryRankRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRankRequest.Merge(m, src)
+}
+func (m *QueryRankRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRankRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRankRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryRankRequest proto.InternalMessageInfo
+
+func (m *QueryRankRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type QueryRankResponse struct {
+	Rank *NodeRank `protobuf:"bytes,1,opt,name=rank,proto3" json:"rank,omitempty"`
+}
+
+func (m *QueryRankResponse) Reset()         { *m = QueryRankResponse{} }
+func (m *QueryRankResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryRankResponse) ProtoMessage()    {}
+func (*QueryRankResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ab10d9aecdb4b4ec, []int{1}
+}
+func (m *QueryRankResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryRankResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRankResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryRankResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRankResponse.Merge(m, src)
+}
+func (m *QueryRankResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRankResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRankResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryRankResponse proto.InternalMessageInfo
+
+func (m *QueryRankResponse) GetRank() *NodeRank {
+	if m != nil {
+		return m.Rank
+	}
+	return nil
+}
+
+type QueryTopRequest struct {
+	Limit uint32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (m *QueryTopRequest) Reset()         { *m = QueryTopRequest{} }
+func (m *QueryTopRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryTopRequest) ProtoMessage()    {}
+func (*QueryTopRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ab10d9aecdb4b4ec, []int{2}
+}
+func (m *QueryTopRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryTopRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryTopRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryTopRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryTopRequest.Merge(m, src)
+}
+func (m *QueryTopRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryTopRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryTopRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryTopRequest proto.InternalMessageInfo
+
+func (m *QueryTopRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type QueryTopResponse struct {
+	Ranks []*NodeRank `protobuf:"bytes,1,rep,name=ranks,proto3" json:"ranks,omitempty"`
+}
+
+func (m *QueryTopResponse) Reset()         { *m = QueryTopResponse{} }
+func (m *QueryTopResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryTopResponse) ProtoMessage()    {}
+func (*QueryTopResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ab10d9aecdb4b4ec, []int{3}
+}
+func (m *QueryTopResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryTopResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryTopResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryTopResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryTopResponse.Merge(m, src)
+}
+func (m *QueryTopResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryTopResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryTopResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryTopResponse proto.InternalMessageInfo
+
+func (m *QueryTopResponse) GetRanks() []*NodeRank {
+	if m != nil {
+		return m.Ranks
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*QueryRankRequest)(nil), "reputation.v1.QueryRankRequest")
+	proto.RegisterType((*QueryRankResponse)(nil), "reputation.v1.QueryRankResponse")
+	proto.RegisterType((*QueryTopRequest)(nil), "reputation.v1.QueryTopRequest")
+	proto.RegisterType((*QueryTopResponse)(nil), "reputation.v1.QueryTopResponse")
+}
+
+func init() { proto.RegisterFile("reputation/v1/query.proto", fileDescriptor_ab10d9aecdb4b4ec) }
+
+var fileDescriptor_ab10d9aecdb4b4ec = []byte{
+	// 349 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0x4f, 0x4b, 0xc3, 0x30,
+	0x18, 0xc6, 0x97, 0xfd, 0x11, 0x8c, 0x4c, 0x67, 0x18, 0x38, 0x8b, 0x64, 0xa5, 0x17, 0x05, 0x59,
+	0xc3, 0xe6, 0xd1, 0x8b, 0xfa, 0x01, 0x04, 0xcb, 0xbc, 0x78, 0xcb, 0xd6, 0x30, 0x43, 0xd7, 0x24,
+	0x6b, 0xd3, 0xe1, 0x10, 0x2f, 0x7e, 0x02, 0xc1, 0x2f, 0xe5, 0x71, 0xe0, 0x65, 0x47, 0xd9, 0xfc,
+	0x20, 0xb2, 0x74, 0xb2, 0xae, 0x38, 0x8f, 0xe1, 0xf9, 0xbd, 0xbf, 0xe7, 0x7d, 0x4b, 0xe1, 0x71,
+	0xc4, 0x54, 0xa2, 0xa9, 0xe6, 0x52, 0x90, 0x71, 0x9b, 0x8c, 0x12, 0x16, 0x4d, 0x5c, 0x15, 0x49,
+	0x2d, 0x51, 0x75, 0x1d, 0xb9, 0xe3, 0xb6, 0x75, 0x32, 0x90, 0x72, 0x30, 0x64, 0x84, 0x2a, 0x4e,
+	0xa8, 0x10, 0x32, 0x4d, 0xe2, 0x14, 0xb6, 0xf0, 0xa6, 0x27, 0x33, 0x6a, 0x72, 0xc7, 0x81, 0xb5,
+	0xbb, 0xa5, 0xdb, 0xa3, 0x22, 0xf0, 0xd8, 0x28, 0x61, 0xb1, 0x46, 0xfb, 0xb0, 0xc8, 0xfd, 0x06,
+	0xb0, 0xc1, 0xd9, 0xae, 0x57, 0xe4, 0xbe, 0x73, 0x05, 0x0f, 0x33, 0x4c, 0xac, 0xa4, 0x88, 0x19,
+	0x3a, 0x87, 0xe5, 0x88, 0x8a, 0xc0, 0x60, 0x7b, 0x9d, 0x23, 0x77, 0x63, 0x29, 0xf7, 0x56, 0xfa,
+	0xcc, 0xe0, 0x06, 0x72, 0x4e, 0xe1, 0x81, 0x31, 0x74, 0xa5, 0xfa, 0x2d, 0xa9, 0xc3, 0xca, 0x90,
+	0x87, 0x5c, 0x1b, 0x41, 0xd5, 0x4b, 0x1f, 0xce, 0x35, 0xac, 0xad, 0xc1, 0x55, 0x53, 0x0b, 0x56,
+	0x96, 0x92, 0xb8, 0x01, 0xec, 0xd2, 0x7f, 0x55, 0x29, 0xd5, 0x99, 0x01, 0x58, 0x31, 0x0e, 0x14,
+	0xc0, 0xf2, 0x32, 0x40, 0xcd, 0xdc, 0x44, 0xfe, 0x60, 0xcb, 0xde, 0x0e, 0xa4, 0x3b, 0x38, 0xf6,
+	0xeb, 0xe7, 0xf7, 0x7b, 0xd1, 0x42, 0x0d, 0x92, 0xfb, 0x9e, 0x54, 0x04, 0xe4, 0x99, 0xfb, 0x2f,
+	0xa8, 0x07, 0x4b, 0x5d, 0xa9, 0x10, 0xfe, 0x4b, 0xb5, 0x3e, 0xdb, 0x6a, 0x6e, 0xcd, 0x57, 0x4d,
+	0x96, 0x69, 0xaa, 0x23, 0x94, 0x6b, 0xd2, 0x52, 0xdd, 0xdc, 0x7f, 0xcc, 0x31, 0x98, 0xce, 0x31,
+	0xf8, 0x9a, 0x63, 0xf0, 0xb6, 0xc0, 0x85, 0xe9, 0x02, 0x17, 0x66, 0x0b, 0x5c, 0x78, 0xb8, 0x1c,
+	0x70, 0xfd, 0x98, 0xf4, 0xdc, 0xbe, 0x0c, 0x49, 0xc4, 0x86, 0x6c, 0x4c, 0x85, 0x6e, 0xf5, 0x65,
+	0x18, 0x26, 0x82, 0xeb, 0x49, 0x56, 0xf5, 0x94, 0x7d, 0xe8, 0x89, 0x62, 0x71, 0x6f, 0xc7, 0xfc,
+	0x0a, 0x17, 0x3f, 0x03, 0x00, 0xeb, 0x17, 0xb1, 0x1d, 0x74, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// QueryClient is the client API for Query service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type QueryClient interface {
+	// Rank returns the ranks of a node
+	Rank(ctx context.Context, in *QueryRankRequest, opts ...grpc.CallOption) (*QueryRankResponse, error)
+	// Top returns the nodes with the highest net rank
+	Top(ctx context.Context, in *QueryTopRequest, opts ...grpc.CallOption) (*QueryTopResponse, error)
+}
+
+type queryClient struct {
+	cc grpc1.ClientConn
+}
+
+func NewQueryClient(cc grpc1.ClientConn) QueryClient {
+	return &queryClient{cc}
+}
+
+func (c *queryClient) Rank(ctx context.Context, in *QueryRankRequest, opts ...grpc.CallOption) (*QueryRankResponse, error) {
+	out := new(QueryRankResponse)
+	err := c.cc.Invoke(ctx, "/reputation.v1.Query/Rank", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) Top(ctx context.Context, in *QueryTopRequest, opts ...grpc.CallOption) (*QueryTopResponse, error) {
+	out := new(QueryTopResponse)
+	err := c.cc.Invoke(ctx, "/reputation.v1.Query/Top", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QueryServer is the server API for Query service.
+type QueryServer interface {
+	// Rank returns the ranks of a node
+	Rank(context.Context, *QueryRankRequest) (*QueryRankResponse, error)
+	// Top returns the nodes with the highest net rank
+	Top(context.Context, *QueryTopRequest) (*QueryTopResponse, error)
+}
+
+// UnimplementedQueryServer can be embedded to have forward compatible implementations.
+type UnimplementedQueryServer struct {
+}
+
+func (*UnimplementedQueryServer) Rank(ctx context.Context, req *QueryRankRequest) (*QueryRankResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rank not implemented")
+}
+func (*UnimplementedQueryServer) Top(ctx context.Context, req *QueryTopRequest) (*QueryTopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Top not implemented")
+}
+
+func RegisterQueryServer(s grpc1.Server, srv QueryServer) {
+	s.RegisterService(&_Query_serviceDesc, srv)
+}
+
+func _Query_Rank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Rank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reputation.v1.Query/Rank",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Rank(ctx, req.(*QueryRankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Top_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryTopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Top(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reputation.v1.Query/Top",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Top(ctx, req.(*QueryTopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Query_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "reputation.v1.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Rank",
+			Handler:    _Query_Rank_Handler,
+		},
+		{
+			MethodName: "Top",
+			Handler:    _Query_Top_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reputation/v1/query.proto",
+}
+
+func (m *QueryRankRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRankRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRankRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRankResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRankResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRankResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Rank != nil {
+		{
+			size, err := m.Rank.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryTopRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryTopRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryTopRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Limit != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryTopResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryTopResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryTopResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Ranks) > 0 {
+		for iNdEx := len(m.Ranks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Ranks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQuery(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
+	offset -= sovQuery(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *QueryRankRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryRankResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Rank != nil {
+		l = m.Rank.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryTopRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Limit != 0 {
+		n += 1 + sovQuery(uint64(m.Limit))
+	}
+	return n
+}
+
+func (m *QueryTopResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Ranks) > 0 {
+		for _, e := range m.Ranks {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozQuery(x uint64) (n int) {
+	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *QueryRankRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryRankRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryRankRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryRankResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryRankResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryRankResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rank", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Rank == nil {
+				m.Rank = &NodeRank{}
+			}
+			if err := m.Rank.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryTopRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryTopRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryTopRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryTopResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryTopResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryTopResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ranks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ranks = append(m.Ranks, &NodeRank{})
+			if err := m.Ranks[len(m.Ranks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipQuery(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthQuery
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupQuery
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthQuery
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthQuery        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowQuery          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupQuery = fmt.Errorf("proto: unexpected end of group")
+)
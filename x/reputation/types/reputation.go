@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/relevant-community/reputation/detrep"
+)
+
+// the proto types of this module are generated from proto/reputation/v1 with scripts/protocgen.sh
+
+// Valid returns true if v is one of the known votes
+func (v Vote) Valid() bool {
+	_, ok := Vote_name[int32(v)]
+	return ok
+}
+
+// Weight returns the signed link weight of the vote
+func (v Vote) Weight() sdk.Int {
+	one := sdk.NewIntWithDecimal(1, detrep.Decimals)
+	switch v {
+	case VoteUp:
+		return one
+	case VoteDown:
+		return one.Neg()
+	}
+	return sdk.ZeroInt()
+}
+
+// ParseVote parses up, down, none or a proto name
+func ParseVote(s string) (Vote, error) {
+	switch strings.ToLower(s) {
+	case "up":
+		return VoteUp, nil
+	case "down":
+		return VoteDown, nil
+	case "none":
+		return VoteNone, nil
+	}
+	if v, ok := Vote_value[strings.ToUpper(s)]; ok {
+		return Vote(v), nil
+	}
+	return VoteNone, fmt.Errorf("invalid vote %s, expected up, down or none", s)
+}
+
+// NewNodeRank converts a detrep result, ranks are written as decimals
+func NewNodeRank(result detrep.Ranks) NodeRank {
+	return NodeRank{
+		Id:    result.ID,
+		PRank: toDec(result.PRank).String(),
+		NRank: toDec(result.NRank).String(),
+	}
+}
+
+// Result parses the ranks back to detrep precision
+func (m NodeRank) Result() (detrep.Ranks, error) {
+	pRank, err := fromDec(m.PRank)
+	if err != nil {
+		return detrep.Ranks{}, fmt.Errorf("invalid pRank for %s: %w", m.Id, err)
+	}
+	nRank, err := fromDec(m.NRank)
+	if err != nil {
+		return detrep.Ranks{}, fmt.Errorf("invalid nRank for %s: %w", m.Id, err)
+	}
+	return detrep.Ranks{ID: m.Id, PRank: pRank, NRank: nRank}, nil
+}
+
+func toDec(u detrep.Uint) sdk.Dec {
+	return sdk.NewDecFromBigIntWithPrec(u.BigInt(), detrep.Decimals)
+}
+
+func fromDec(s string) (detrep.Uint, error) {
+	dec, err := sdk.NewDecFromStr(s)
+	if err != nil {
+		return detrep.Uint{}, err
+	}
+	if dec.IsNegative() {
+		return detrep.Uint{}, fmt.Errorf("rank cannot be negative: %s", s)
+	}
+	return detrep.NewUintFromBigInt(dec.BigInt()), nil
+}
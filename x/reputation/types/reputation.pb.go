@@ -0,0 +1,458 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: reputation/v1/reputation.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Vote is the direction of a vote
+type Vote int32
+
+const (
+	// VOTE_NONE removes a previous vote
+	VoteNone Vote = 0
+	// VOTE_UP is a positive link
+	VoteUp Vote = 1
+	// VOTE_DOWN is a negative link
+	VoteDown Vote = 2
+)
+
+var Vote_name = map[int32]string{
+	0: "VOTE_NONE",
+	1: "VOTE_UP",
+	2: "VOTE_DOWN",
+}
+
+var Vote_value = map[string]int32{
+	"VOTE_NONE": 0,
+	"VOTE_UP":   1,
+	"VOTE_DOWN": 2,
+}
+
+func (x Vote) String() string {
+	return proto.EnumName(Vote_name, int32(x))
+}
+
+func (Vote) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_01ba3e22bd123f51, []int{0}
+}
+
+// NodeRank holds the ranks of a node as decimal strings
+type NodeRank struct {
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PRank string `protobuf:"bytes,2,opt,name=p_rank,json=pRank,proto3" json:"p_rank,omitempty"`
+	NRank string `protobuf:"bytes,3,opt,name=n_rank,json=nRank,proto3" json:"n_rank,omitempty"`
+}
+
+func (m *NodeRank) Reset()         { *m = NodeRank{} }
+func (m *NodeRank) String() string { return proto.CompactTextString(m) }
+func (*NodeRank) ProtoMessage()    {}
+func (*NodeRank) Descriptor() ([]byte, []int) {
+	return fileDescriptor_01ba3e22bd123f51, []int{0}
+}
+func (m *NodeRank) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NodeRank) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NodeRank.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NodeRank) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeRank.Merge(m, src)
+}
+func (m *NodeRank) XXX_Size() int {
+	return m.Size()
+}
+func (m *NodeRank) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeRank.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeRank proto.InternalMessageInfo
+
+func (m *NodeRank) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeRank) GetPRank() string {
+	if m != nil {
+		return m.PRank
+	}
+	return ""
+}
+
+func (m *NodeRank) GetNRank() string {
+	if m != nil {
+		return m.NRank
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("reputation.v1.Vote", Vote_name, Vote_value)
+	proto.RegisterType((*NodeRank)(nil), "reputation.v1.NodeRank")
+}
+
+func init() { proto.RegisterFile("reputation/v1/reputation.proto", fileDescriptor_01ba3e22bd123f51) }
+
+var fileDescriptor_01ba3e22bd123f51 = []byte{
+	// 271 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2b, 0x4a, 0x2d, 0x28,
+	0x2d, 0x49, 0x2c, 0xc9, 0xcc, 0xcf, 0xd3, 0x2f, 0x33, 0xd4, 0x47, 0xf0, 0xf4, 0x0a, 0x8a, 0xf2,
+	0x4b, 0xf2, 0x85, 0x78, 0x91, 0x44, 0xca, 0x0c, 0xa5, 0x44, 0xd2, 0xf3, 0xd3, 0xf3, 0xc1, 0x32,
+	0xfa, 0x20, 0x16, 0x44, 0x91, 0x92, 0x07, 0x17, 0x87, 0x5f, 0x7e, 0x4a, 0x6a, 0x50, 0x62, 0x5e,
+	0xb6, 0x10, 0x1f, 0x17, 0x53, 0x66, 0x8a, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x67, 0x10, 0x53, 0x66,
+	0x8a, 0x90, 0x28, 0x17, 0x5b, 0x41, 0x7c, 0x51, 0x62, 0x5e, 0xb6, 0x04, 0x13, 0x58, 0x8c, 0xb5,
+	0x00, 0xac, 0x4c, 0x94, 0x8b, 0x2d, 0x0f, 0x22, 0xcc, 0x0c, 0x11, 0xce, 0x03, 0x09, 0x6b, 0xc5,
+	0x73, 0xb1, 0x84, 0xe5, 0x97, 0xa4, 0x0a, 0x49, 0x73, 0x71, 0x86, 0xf9, 0x87, 0xb8, 0xc6, 0xfb,
+	0xf9, 0xfb, 0xb9, 0x0a, 0x30, 0x48, 0xf1, 0x74, 0xcd, 0x55, 0xe0, 0x00, 0x49, 0xf8, 0xe5, 0xe7,
+	0xa5, 0x0a, 0x89, 0x73, 0xb1, 0x83, 0x25, 0x43, 0x03, 0x04, 0x18, 0xa5, 0xb8, 0xba, 0xe6, 0x2a,
+	0xb0, 0x81, 0xa4, 0x42, 0x0b, 0xe0, 0xba, 0x5c, 0xfc, 0xc3, 0xfd, 0x04, 0x98, 0x10, 0xba, 0x5c,
+	0xf2, 0xcb, 0xf3, 0xa4, 0x58, 0x3a, 0x16, 0xcb, 0x31, 0x38, 0x85, 0x9e, 0x78, 0x24, 0xc7, 0x78,
+	0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47, 0x72, 0x8c, 0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7,
+	0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x94, 0x75, 0x7a, 0x66, 0x49, 0x46, 0x69, 0x92, 0x5e, 0x72, 0x7e,
+	0xae, 0x7e, 0x51, 0x6a, 0x4e, 0x6a, 0x59, 0x62, 0x5e, 0x89, 0x6e, 0x72, 0x7e, 0x6e, 0x6e, 0x69,
+	0x5e, 0x66, 0x49, 0x25, 0x52, 0xc8, 0xe8, 0x57, 0x20, 0x73, 0x4a, 0x2a, 0x0b, 0x52, 0x8b, 0x93,
+	0xd8, 0xc0, 0x01, 0x61, 0x0c, 0x18, 0x00, 0x00, 0xbe, 0xe2, 0x04, 0x4f, 0x01, 0x00, 0x00,
+}
+
+func (m *NodeRank) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NodeRank) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NodeRank) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NRank) > 0 {
+		i -= len(m.NRank)
+		copy(dAtA[i:], m.NRank)
+		i = encodeVarintReputation(dAtA, i, uint64(len(m.NRank)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.PRank) > 0 {
+		i -= len(m.PRank)
+		copy(dAtA[i:], m.PRank)
+		i = encodeVarintReputation(dAtA, i, uint64(len(m.PRank)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintReputation(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintReputation(dAtA []byte, offset int, v uint64) int {
+	offset -= sovReputation(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *NodeRank) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovReputation(uint64(l))
+	}
+	l = len(m.PRank)
+	if l > 0 {
+		n += 1 + l + sovReputation(uint64(l))
+	}
+	l = len(m.NRank)
+	if l > 0 {
+		n += 1 + l + sovReputation(uint64(l))
+	}
+	return n
+}
+
+func sovReputation(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozReputation(x uint64) (n int) {
+	return sovReputation(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *NodeRank) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowReputation
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NodeRank: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NodeRank: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReputation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthReputation
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthReputation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PRank", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReputation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthReputation
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthReputation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PRank = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NRank", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReputation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthReputation
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthReputation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NRank = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipReputation(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthReputation
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipReputation(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowReputation
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowReputation
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowReputation
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthReputation
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupReputation
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthReputation
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthReputation        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowReputation          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupReputation = fmt.Errorf("proto: unexpected end of group")
+)
@@ -0,0 +1,165 @@
+package types
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/relevant-community/reputation/detrep"
+	"google.golang.org/grpc/encoding"
+	grpcproto "google.golang.org/grpc/encoding/proto"
+)
+
+var voter = sdk.AccAddress([]byte("voter_______________"))
+
+func TestMsgVoteValidateBasic(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *MsgVote
+		ok   bool
+	}{
+		{"up", NewMsgVote(voter, "post1", VoteUp), true},
+		{"none", NewMsgVote(voter, "post1", VoteNone), true},
+		{"bad voter", &MsgVote{Voter: "voter", Target: "post1", Vote: VoteUp}, false},
+		{"empty target", NewMsgVote(voter, "", VoteUp), false},
+		{"self vote", NewMsgVote(voter, voter.String(), VoteDown), false},
+		{"unknown vote", NewMsgVote(voter, "post1", Vote(7)), false},
+	}
+	for _, tt := range tests {
+		if err := tt.msg.ValidateBasic(); (err == nil) != tt.ok {
+			t.Errorf("%s: unexpected result %v", tt.name, err)
+		}
+	}
+}
+
+func TestAnyRoundTrip(t *testing.T) {
+	registry := codectypes.NewInterfaceRegistry()
+	RegisterInterfaces(registry)
+
+	msg := NewMsgVote(voter, "post1", VoteDown)
+	any, err := codectypes.NewAnyWithValue(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if any.TypeUrl != "/reputation.v1.MsgVote" {
+		t.Errorf("unexpected type url %s", any.TypeUrl)
+	}
+
+	var decoded sdk.Msg
+	if err := registry.UnpackAny(&codectypes.Any{TypeUrl: any.TypeUrl, Value: any.Value}, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if *decoded.(*MsgVote) != *msg {
+		t.Errorf("expected %v got %v", msg, decoded)
+	}
+
+	proposal := NewPersonalizationProposal("seeds", "add seeds", []string{"a", "b"}, nil)
+	any, err = codectypes.NewAnyWithValue(proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var content govtypes.Content
+	if err := registry.UnpackAny(&codectypes.Any{TypeUrl: any.TypeUrl, Value: any.Value}, &content); err != nil {
+		t.Fatal(err)
+	}
+	if len(content.(*PersonalizationProposal).Add) != 2 {
+		t.Errorf("expected 2 nodes to add, got %v", content)
+	}
+}
+
+func TestQueryCodec(t *testing.T) {
+	// this is the codec used by the gRPC query router
+	codec := encoding.GetCodec(grpcproto.Name)
+
+	res := &QueryTopResponse{Ranks: []*NodeRank{
+		{Id: "a", PRank: "0.5", NRank: "0.0"},
+		{Id: "b", PRank: "0.25", NRank: "0.1"},
+	}}
+	bz, err := codec.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded QueryTopResponse
+	if err := codec.Unmarshal(bz, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Ranks) != 2 || *decoded.Ranks[1] != *res.Ranks[1] {
+		t.Errorf("expected %v got %v", res, decoded)
+	}
+}
+
+func TestQueryJSON(t *testing.T) {
+	// this is the codec the CLI prints query responses with
+	registry := codectypes.NewInterfaceRegistry()
+	RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+
+	res := &QueryRankResponse{Rank: &NodeRank{Id: "a", PRank: "0.5", NRank: "0.1"}}
+	bz, err := cdc.MarshalJSON(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded QueryRankResponse
+	if err := cdc.UnmarshalJSON(bz, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if *decoded.Rank != *res.Rank {
+		t.Errorf("expected %v got %v", res, decoded)
+	}
+
+	var out bytes.Buffer
+	clientCtx := client.Context{}.WithJSONMarshaler(cdc).WithOutput(&out).WithOutputFormat("json")
+	if err := clientCtx.PrintProto(&QueryTopResponse{Ranks: []*NodeRank{res.Rank}}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"ranks":[{"id":"a","p_rank":"0.5","n_rank":"0.1"}]}`; strings.TrimSpace(out.String()) != expected {
+		t.Errorf("expected %s got %s", expected, out.String())
+	}
+
+	if _, err := cdc.MarshalJSON(NewMsgVote(voter, "post1", VoteUp)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNodeRankResult(t *testing.T) {
+	rank := NodeRank{Id: "a", PRank: "0.250000000000000000", NRank: "0.000000000000000001"}
+	result, err := rank.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.NRank.Equal(detrep.OneUint()) || NewNodeRank(result) != rank {
+		t.Errorf("ranks should round trip %v %v", rank, NewNodeRank(result))
+	}
+	if _, err := (NodeRank{Id: "a", PRank: "-1", NRank: "0"}).Result(); err == nil {
+		t.Errorf("expected an error for a negative rank")
+	}
+}
+
+func TestParams(t *testing.T) {
+	if err := DefaultGenesis().Validate(); err != nil {
+		t.Error(err)
+	}
+	params := DefaultParams()
+	params.Alpha = sdk.NewDec(2)
+	if err := params.Validate(); err == nil {
+		t.Errorf("alpha should be at most 1")
+	}
+	params = DefaultParams()
+	params.Epsilon = sdk.NewDecWithPrec(1, 18)
+	if err := params.Validate(); err == nil {
+		t.Errorf("epsilon should be at least %s", MinEpsilon)
+	}
+	params.Epsilon = MinEpsilon
+	if err := params.Validate(); err != nil {
+		t.Error(err)
+	}
+	graphParams := DefaultParams().GraphParams()
+	if graphParams.Alpha.String() != "850000000000000000" {
+		t.Errorf("unexpected alpha %s", graphParams.Alpha)
+	}
+}